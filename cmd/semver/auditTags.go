@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nickwells/filecheck.mod/filecheck"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+)
+
+// severity gives the importance of an audit finding
+type severity int
+
+// These are the severities of the audit findings, in increasing order
+const (
+	sevInfo severity = iota
+	sevWarning
+	sevError
+)
+
+// severityNames maps the severities to their names
+var severityNames = map[severity]string{
+	sevInfo:    "info",
+	sevWarning: "warning",
+	sevError:   "error",
+}
+
+// String returns the name of the severity
+func (s severity) String() string {
+	return severityNames[s]
+}
+
+// MarshalText returns the name of the severity, it is used when the
+// findings are written as JSON
+func (s severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// These are the kinds of audit finding
+const (
+	findUnparsedTag   = "unparsed-tag"
+	findFailedChecks  = "failed-checks"
+	findDupVersion    = "duplicate-version"
+	findPatchGap      = "patch-gap"
+	findMinorGap      = "minor-gap"
+	findMajorGap      = "major-gap"
+	findLatePreRelTag = "late-pre-release"
+)
+
+// finding records a problem found with the tags
+type finding struct {
+	Severity severity `json:"severity"`
+	Kind     string   `json:"kind"`
+	Tags     []string `json:"tags"`
+	Message  string   `json:"message"`
+}
+
+// String returns the finding in a human-readable form
+func (f finding) String() string {
+	return fmt.Sprintf("%-7s %s: %s: %s",
+		f.Severity, f.Kind, strings.Join(f.Tags, ", "), f.Message)
+}
+
+// These are the values that can be given to the audit output parameter
+const (
+	auditOutText = "text"
+	auditOutJSON = "json"
+)
+
+// auditOpts holds the values set by the audit-tags parameters
+type auditOpts struct {
+	repoDir string
+	output  string
+	failOn  string
+}
+
+// auditTags checks the version tags and the unparsed tags and returns the
+// findings. The tags must be in increasing order of version number (as
+// returned by semverparams.ReadAllRepoTags).
+func auditTags(
+	tags []semverparams.RepoTag,
+	unparsed []semverparams.UnparsedTag,
+	svCks semverparams.SemverChecks,
+) []finding {
+	findings := []finding{}
+
+	for _, ut := range unparsed {
+		findings = append(findings, finding{
+			Severity: sevWarning,
+			Kind:     findUnparsedTag,
+			Tags:     []string{ut.Name},
+			Message:  ut.Err.Error(),
+		})
+	}
+
+	for _, tag := range tags {
+		if err := svCks.Check(&tag.SemVer); err != nil {
+			findings = append(findings, finding{
+				Severity: sevError,
+				Kind:     findFailedChecks,
+				Tags:     []string{tag.Name},
+				Message:  err.Error(),
+			})
+		}
+	}
+
+	findings = append(findings, findDupVersions(tags)...)
+	findings = append(findings, findGaps(tags)...)
+	findings = append(findings, findLatePreRels(tags)...)
+
+	return findings
+}
+
+// versionNoBuild returns the semantic version number without any build IDs
+func versionNoBuild(sv *semver.SV) string {
+	s, _, _ := strings.Cut(sv.String(), "+")
+	return s
+}
+
+// findDupVersions returns a finding for each version having more than one
+// tag. Such tags differ only in their build IDs.
+func findDupVersions(tags []semverparams.RepoTag) []finding {
+	var findings []finding
+
+	for i := 0; i < len(tags); {
+		v := versionNoBuild(&tags[i].SemVer)
+		names := []string{tags[i].Name}
+
+		j := i + 1
+		for ; j < len(tags) && versionNoBuild(&tags[j].SemVer) == v; j++ {
+			names = append(names, tags[j].Name)
+		}
+
+		if len(names) > 1 {
+			findings = append(findings, finding{
+				Severity: sevError,
+				Kind:     findDupVersion,
+				Tags:     names,
+				Message: fmt.Sprintf(
+					"%d tags give %s, differing only in their build IDs",
+					len(names), v),
+			})
+		}
+
+		i = j
+	}
+
+	return findings
+}
+
+// gapFinding returns a finding for the versions missing between the two
+// tags
+func gapFinding(kind, from, to, missing string) finding {
+	return finding{
+		Severity: sevInfo,
+		Kind:     kind,
+		Tags:     []string{from, to},
+		Message:  "missing " + missing,
+	}
+}
+
+// findGaps returns a finding for each gap in the sequence of released
+// versions (those without pre-release IDs). A gap is reported for any
+// missing major versions, for any missing minor versions within a major
+// version and for any missing patch versions within a minor version. The
+// first version of a new major or minor version is expected to have a
+// minor and patch version, or a patch version, of 0 and any versions
+// before it are reported as missing.
+func findGaps(tags []semverparams.RepoTag) []finding {
+	var (
+		findings []finding
+		prev     *semverparams.RepoTag
+	)
+
+	for i := range tags {
+		tag := &tags[i]
+		if tag.SemVer.HasPreRelIDs() {
+			continue
+		}
+
+		if prev != nil {
+			findings = append(findings, versionGaps(prev, tag)...)
+		}
+
+		prev = tag
+	}
+
+	return findings
+}
+
+// versionGaps returns a finding for each gap between the two released
+// versions
+func versionGaps(prev, tag *semverparams.RepoTag) []finding {
+	var findings []finding
+
+	pv, v := &prev.SemVer, &tag.SemVer
+
+	addGap := func(kind, pfx string, lo, hi int) {
+		if lo > hi {
+			return
+		}
+
+		findings = append(findings,
+			gapFinding(kind, prev.Name, tag.Name, missingRange(pfx, lo, hi)))
+	}
+
+	var (
+		majorPfx = "v"
+		minorPfx = fmt.Sprintf("v%d.", v.Major())
+		patchPfx = fmt.Sprintf("v%d.%d.", v.Major(), v.Minor())
+	)
+
+	switch {
+	case v.Major() > pv.Major():
+		addGap(findMajorGap, majorPfx, pv.Major()+1, v.Major()-1)
+		addGap(findMinorGap, minorPfx, 0, v.Minor()-1)
+		addGap(findPatchGap, patchPfx, 0, v.Patch()-1)
+	case v.Minor() > pv.Minor():
+		addGap(findMinorGap, minorPfx, pv.Minor()+1, v.Minor()-1)
+		addGap(findPatchGap, patchPfx, 0, v.Patch()-1)
+	default:
+		addGap(findPatchGap, patchPfx, pv.Patch()+1, v.Patch()-1)
+	}
+
+	return findings
+}
+
+// missingRange describes the missing versions from lo to hi
+func missingRange(pfx string, lo, hi int) string {
+	if lo == hi {
+		return fmt.Sprintf("%s%d", pfx, lo)
+	}
+
+	return fmt.Sprintf("%s%d to %s%d", pfx, lo, pfx, hi)
+}
+
+// findLatePreRels returns a finding for each pre-release whose tagged
+// commit is newer than that of the corresponding release
+func findLatePreRels(tags []semverparams.RepoTag) []finding {
+	var findings []finding
+
+	releases := map[string]semverparams.RepoTag{}
+
+	for _, tag := range tags {
+		if !tag.SemVer.HasPreRelIDs() {
+			releases[coreVersion(&tag.SemVer)] = tag
+		}
+	}
+
+	for _, tag := range tags {
+		if !tag.SemVer.HasPreRelIDs() {
+			continue
+		}
+
+		rel, ok := releases[coreVersion(&tag.SemVer)]
+		if !ok || !tag.Time.After(rel.Time) {
+			continue
+		}
+
+		findings = append(findings, finding{
+			Severity: sevWarning,
+			Kind:     findLatePreRelTag,
+			Tags:     []string{tag.Name, rel.Name},
+			Message: "the pre-release is of a newer commit" +
+				" than the release",
+		})
+	}
+
+	return findings
+}
+
+// coreVersion returns the major, minor and patch parts of the semantic
+// version number
+func coreVersion(sv *semver.SV) string {
+	return fmt.Sprintf("v%d.%d.%d", sv.Major(), sv.Minor(), sv.Patch())
+}
+
+// writeFindings writes the findings in the given output format
+func writeFindings(w io.Writer, findings []finding, output string) error {
+	if output == auditOutJSON {
+		content, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(w, string(content))
+
+		return err
+	}
+
+	if len(findings) == 0 {
+		_, err := fmt.Fprintln(w, "no problems found")
+		return err
+	}
+
+	for _, f := range findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAuditParams returns a function that will add the parameters for the
+// audit-tags command to the passed PSet
+func addAuditParams(ao *auditOpts) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.Add("repo-dir",
+			psetter.Pathname{
+				Value:       &ao.repoDir,
+				Expectation: filecheck.DirExists(),
+			},
+			"a directory in the git repository whose tags are audited",
+		)
+
+		ps.Add("output",
+			psetter.Enum[string]{
+				Value: &ao.output,
+				AllowedVals: psetter.AllowedVals[string]{
+					auditOutText: "one line per finding",
+					auditOutJSON: "a JSON array of findings",
+				},
+			},
+			"the form in which the findings are shown",
+		)
+
+		ps.Add("fail-on",
+			psetter.Enum[string]{
+				Value: &ao.failOn,
+				AllowedVals: psetter.AllowedVals[string]{
+					sevInfo.String():    "fail for any finding",
+					sevWarning.String(): "fail for warnings and errors",
+					sevError.String():   "fail only for errors",
+				},
+			},
+			"the lowest severity of finding which will cause the"+
+				" program to exit with a failure status",
+		)
+
+		return nil
+	}
+}
+
+// runAuditTags reads the tags of the git repository and writes any
+// problems found. The exit status shows whether there were findings at or
+// above the fail-on severity.
+func runAuditTags(p *prog, w io.Writer) int {
+	tags, unparsed, err := semverparams.ReadAllRepoTags(
+		p.auditOpts.repoDir, p.tagPrefix)
+	if err != nil {
+		return reportErr(err)
+	}
+
+	findings := auditTags(tags, unparsed, p.svCks)
+
+	if err := writeFindings(w, findings, p.auditOpts.output); err != nil {
+		return reportErr(err)
+	}
+
+	if hasFindingsAtLevel(findings, p.auditOpts.failOn) {
+		return exitFail
+	}
+
+	return exitOK
+}
+
+// hasFindingsAtLevel returns true if any of the findings has a severity at
+// or above the named severity
+func hasFindingsAtLevel(findings []finding, sevName string) bool {
+	level := severityByName(sevName)
+
+	for _, f := range findings {
+		if f.Severity >= level {
+			return true
+		}
+	}
+
+	return false
+}
+
+// severityByName returns the severity with the given name
+func severityByName(name string) severity {
+	for s, n := range severityNames {
+		if n == name {
+			return s
+		}
+	}
+
+	return sevError
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// mkRepoTag returns a RepoTag for the version, tagged at the given number
+// of days after a fixed time
+func mkRepoTag(version string, days int) semverparams.RepoTag {
+	sv, err := semver.ParseSV(version)
+	if err != nil {
+		panic(err)
+	}
+
+	return semverparams.RepoTag{
+		Name:   version,
+		SemVer: *sv,
+		Time:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, days),
+	}
+}
+
+func TestAuditTags(t *testing.T) {
+	tags := []semverparams.RepoTag{
+		mkRepoTag("v1.0.0", 0),
+		mkRepoTag("v1.0.3", 1),
+		mkRepoTag("v1.3.0+b1", 2),
+		mkRepoTag("v1.3.0+b2", 3),
+		mkRepoTag("v1.4.0-rc.1", 4),
+		mkRepoTag("v1.4.0-rc.2", 6),
+		mkRepoTag("v1.4.0", 5),
+		mkRepoTag("v2.1.0", 7),
+	}
+	unparsed := []semverparams.UnparsedTag{
+		{Name: "junk", Err: errors.New("bad")},
+	}
+	svCks := semverparams.SemverChecks{
+		BuildIDChecks: []check.ValCk[[]string]{
+			check.SliceLength[[]string](check.ValLT(1)),
+		},
+	}
+
+	findings := auditTags(tags, unparsed, svCks)
+
+	var got []string
+
+	for _, f := range findings {
+		got = append(got,
+			f.Severity.String()+" "+f.Kind+" "+strings.Join(f.Tags, ","))
+	}
+
+	testhelper.DiffStringSlice(t, "auditTags", "findings", got, []string{
+		"warning unparsed-tag junk",
+		"error failed-checks v1.3.0+b1",
+		"error failed-checks v1.3.0+b2",
+		"error duplicate-version v1.3.0+b1,v1.3.0+b2",
+		"info patch-gap v1.0.0,v1.0.3",
+		"info minor-gap v1.0.3,v1.3.0+b1",
+		"info minor-gap v1.4.0,v2.1.0",
+		"warning late-pre-release v1.4.0-rc.2,v1.4.0",
+	})
+
+	testCases := []struct {
+		testhelper.ID
+		findings []finding
+		failOn   string
+		expFail  bool
+	}{
+		{
+			ID:       testhelper.MkID("all findings, fail on error"),
+			findings: findings,
+			failOn:   "error",
+			expFail:  true,
+		},
+		{
+			ID:       testhelper.MkID("info only, fail on warning"),
+			findings: findings[4:6],
+			failOn:   "warning",
+		},
+		{
+			ID:       testhelper.MkID("info only, fail on info"),
+			findings: findings[4:6],
+			failOn:   "info",
+			expFail:  true,
+		},
+		{
+			ID:     testhelper.MkID("no findings"),
+			failOn: "info",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "fail",
+			hasFindingsAtLevel(tc.findings, tc.failOn), tc.expFail)
+	}
+}
+
+func TestFindGaps(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		versions []string
+		expGaps  []string
+	}{
+		{
+			ID:       testhelper.MkID("no gaps"),
+			versions: []string{"v1.0.0", "v1.0.1", "v1.1.0", "v2.0.0"},
+		},
+		{
+			ID:       testhelper.MkID("patch gap"),
+			versions: []string{"v1.2.0", "v1.2.3"},
+			expGaps:  []string{"patch-gap v1.2.0,v1.2.3: missing v1.2.1 to v1.2.2"},
+		},
+		{
+			ID:       testhelper.MkID("new minor, missing leading patches"),
+			versions: []string{"v1.2.0", "v1.3.2"},
+			expGaps:  []string{"patch-gap v1.2.0,v1.3.2: missing v1.3.0 to v1.3.1"},
+		},
+		{
+			ID:       testhelper.MkID("minor and leading patch gaps"),
+			versions: []string{"v1.2.0", "v1.4.1"},
+			expGaps: []string{
+				"minor-gap v1.2.0,v1.4.1: missing v1.3",
+				"patch-gap v1.2.0,v1.4.1: missing v1.4.0",
+			},
+		},
+		{
+			ID:       testhelper.MkID("major gap"),
+			versions: []string{"v1.4.0", "v3.0.0"},
+			expGaps:  []string{"major-gap v1.4.0,v3.0.0: missing v2"},
+		},
+		{
+			ID:       testhelper.MkID("new major, missing first release"),
+			versions: []string{"v1.4.0", "v2.1.0"},
+			expGaps:  []string{"minor-gap v1.4.0,v2.1.0: missing v2.0"},
+		},
+		{
+			ID:       testhelper.MkID("new major, missing first minor and patch"),
+			versions: []string{"v1.4.0", "v2.0.0-rc.1", "v2.2.3"},
+			expGaps: []string{
+				"minor-gap v1.4.0,v2.2.3: missing v2.0 to v2.1",
+				"patch-gap v1.4.0,v2.2.3: missing v2.2.0 to v2.2.2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var tags []semverparams.RepoTag
+		for i, v := range tc.versions {
+			tags = append(tags, mkRepoTag(v, i))
+		}
+
+		var got []string
+		for _, f := range findGaps(tags) {
+			got = append(got,
+				f.Kind+" "+strings.Join(f.Tags, ",")+": "+f.Message)
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "gaps", got, tc.expGaps)
+	}
+}
+
+func TestWriteFindings(t *testing.T) {
+	findings := []finding{
+		{
+			Severity: sevError,
+			Kind:     findDupVersion,
+			Tags:     []string{"v1.0.0+a", "v1.0.0+b"},
+			Message:  "dup",
+		},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		findings []finding
+		output   string
+		expOut   string
+	}{
+		{
+			ID:       testhelper.MkID("text"),
+			findings: findings,
+			output:   auditOutText,
+			expOut: "error   duplicate-version:" +
+				" v1.0.0+a, v1.0.0+b: dup\n",
+		},
+		{
+			ID:       testhelper.MkID("text, no findings"),
+			findings: []finding{},
+			output:   auditOutText,
+			expOut:   "no problems found\n",
+		},
+		{
+			ID:       testhelper.MkID("json"),
+			findings: findings,
+			output:   auditOutJSON,
+			expOut: `[
+  {
+    "severity": "error",
+    "kind": "duplicate-version",
+    "tags": [
+      "v1.0.0+a",
+      "v1.0.0+b"
+    ],
+    "message": "dup"
+  }
+]
+`,
+		},
+		{
+			ID:       testhelper.MkID("json, no findings"),
+			findings: []finding{},
+			output:   auditOutJSON,
+			expOut:   "[]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var b strings.Builder
+
+		if err := writeFindings(&b, tc.findings, tc.output); err != nil {
+			t.Log(tc.IDStr())
+			t.Error("\t: unexpected error:", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "output", b.String(), tc.expOut)
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+)
+
+// prog holds the values set by the parameters
+type prog struct {
+	svv   semverparams.SemverVals
+	other semverparams.SemverVals
+	svCks semverparams.SemverChecks
+
+	// tagPrefix is the directory part of the tag names in lines read by
+	// the sort and filter commands and of the tags read by the audit-tags
+	// command
+	tagPrefix  string
+	sortOpts   sortOpts
+	filterOpts filterOpts
+	auditOpts  auditOpts
+
+	// stdin is the source of input for those commands which read it
+	stdin io.Reader
+}
+
+// newProg returns a new prog with the semantic version numbers marked as
+// mandatory, reading from the standard input
+func newProg() *prog {
+	return &prog{
+		svv: semverparams.SemverVals{
+			SemverAttrs: param.MustBeSet,
+		},
+		other: semverparams.SemverVals{
+			Prefix:      "other",
+			Desc:        "other",
+			SemverAttrs: param.MustBeSet,
+		},
+		sortOpts:   sortOpts{badLines: badLinesReject},
+		filterOpts: filterOpts{keep: keepAll},
+		auditOpts: auditOpts{
+			repoDir: ".",
+			output:  auditOutText,
+			failOn:  sevError.String(),
+		},
+		stdin: os.Stdin,
+	}
+}
+
+// command describes one of the program's commands
+type command struct {
+	// desc describes the command, it is shown in the usage message
+	desc string
+	// params returns the functions adding the command's parameters
+	params func(p *prog) []param.PSetOptFunc
+	// run performs the command once the parameters have been parsed,
+	// writing any output to the writer, and returns the exit status
+	run func(p *prog, w io.Writer) int
+}
+
+// commands maps the command names to the commands
+var commands = map[string]command{
+	"parse": {
+		desc: "print the parts of the semantic version number",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svv.AddSemverParam(nil),
+				p.svv.AddOutputParams(),
+			}
+		},
+		run: runParse,
+	},
+	"validate": {
+		desc: "check the semantic version number, the exit status" +
+			" shows whether it is valid",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svv.AddSemverParam(&p.svCks),
+				p.svCks.AddCheckParams(),
+			}
+		},
+		run: func(_ *prog, _ io.Writer) int { return exitOK },
+	},
+	"compare": {
+		desc: "print -1, 0 or 1 as the semantic version number is less" +
+			" than, equal to or greater than the other",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svv.AddSemverParam(nil),
+				p.other.AddSemverParam(nil),
+			}
+		},
+		run: runCompare,
+	},
+	"bump": {
+		desc: "increment the semantic version number and print it",
+		params: func(p *prog) []param.PSetOptFunc {
+			// the bump params are added first so that the bump is
+			// applied before the checks on the IDs
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svv.AddBumpParams(),
+				p.svv.AddSemverParam(&p.svCks),
+				p.svCks.AddCheckParams(),
+				p.svv.AddFormatParam(),
+				requireParam("bump"),
+			}
+		},
+		run: runFormat,
+	},
+	"format": {
+		desc: "print the semantic version number in the given format",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svv.AddSemverParam(nil),
+				p.svv.AddFormatParam(),
+			}
+		},
+		run: runFormat,
+	},
+	"sort": {
+		desc: "read semantic version numbers, one per line, from the" +
+			" standard input and print them sorted by precedence",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				addTagPrefixParam(&p.tagPrefix),
+				addSortParams(&p.sortOpts),
+			}
+		},
+		run: runSort,
+	},
+	"filter": {
+		desc: "read semantic version numbers, one per line, from the" +
+			" standard input or a file and print those in a range",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svCks.AddCheckParams(),
+				addTagPrefixParam(&p.tagPrefix),
+				addFilterParams(&p.filterOpts),
+			}
+		},
+		run: runFilter,
+	},
+	"audit-tags": {
+		desc: "check the version tags of a git repository and report" +
+			" any problems",
+		params: func(p *prog) []param.PSetOptFunc {
+			return []param.PSetOptFunc{
+				semverparams.AddVersionParam,
+				p.svCks.AddCheckParams(),
+				addTagPrefixParam(&p.tagPrefix),
+				addAuditParams(&p.auditOpts),
+			}
+		},
+		run: runAuditTags,
+	},
+}
+
+// requireParam returns a function which will add a final check that the
+// named parameter has been set
+func requireParam(name string) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.AddFinalCheck(func() error {
+			p, err := ps.GetParamByName(name)
+			if err != nil {
+				return err
+			}
+
+			if !p.HasBeenSet() {
+				return fmt.Errorf("the %q parameter must be given", name)
+			}
+
+			return nil
+		})
+
+		return nil
+	}
+}
+
+// reportErr reports the error and returns the failure exit status
+func reportErr(err error) int {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+
+	return exitFail
+}
+
+// runParse writes the semantic version number and its parts in the output
+// format. The parts are written as dotenv lines by default. The output goes
+// to the writer unless an output file is given or implied by the format.
+func runParse(p *prog, w io.Writer) int {
+	if p.svv.OutputFormat == "" {
+		p.svv.OutputFormat = semverparams.OutputDotenv
+	}
+
+	if p.svv.OutputFile != "" ||
+		p.svv.OutputFormat == semverparams.OutputGitHub {
+		if err := p.svv.WriteOutput(); err != nil {
+			return reportErr(err)
+		}
+
+		return exitOK
+	}
+
+	content, err := semverparams.NewVersionOutput(&p.svv.SemVer).
+		Marshal(p.svv.OutputFormat)
+	if err != nil {
+		return reportErr(err)
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return reportErr(err)
+	}
+
+	return exitOK
+}
+
+// runCompare writes the result of comparing the semantic version number
+// with the other according to the precedence rules of the Semantic
+// Versioning spec
+func runCompare(p *prog, w io.Writer) int {
+	cmp := 0
+
+	switch {
+	case semver.Less(&p.svv.SemVer, &p.other.SemVer):
+		cmp = -1
+	case semver.Less(&p.other.SemVer, &p.svv.SemVer):
+		cmp = 1
+	}
+
+	fmt.Fprintln(w, cmp)
+
+	return exitOK
+}
+
+// runFormat writes the semantic version number in the format given by the
+// semver-format parameter
+func runFormat(p *prog, w io.Writer) int {
+	s, err := p.svv.FormattedSemVer()
+	if err != nil {
+		return reportErr(err)
+	}
+
+	fmt.Fprintln(w, s)
+
+	return exitOK
+}
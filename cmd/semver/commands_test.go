@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// testManifest is a project manifest file giving a version of v1.4.0-rc.2
+const testManifest = "../../semverparams/testdata/manifest/npm/package.json"
+
+func TestCommands(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cmdName string
+		args    []string
+		expErrs bool
+		expOut  string
+	}{
+		{
+			ID:      testhelper.MkID("parse"),
+			cmdName: "parse",
+			args:    []string{"-semver", "v1.2.3-rc.1+b.2"},
+			expOut: "VERSION=v1.2.3-rc.1+b.2\n" +
+				"VERSION_CORE=1.2.3\n" +
+				"VERSION_MAJOR=1\n" +
+				"VERSION_MINOR=2\n" +
+				"VERSION_PATCH=3\n" +
+				"VERSION_PRERELEASE=rc.1\n" +
+				"VERSION_BUILD=b.2\n" +
+				"VERSION_IS_PRERELEASE=true\n",
+		},
+		{
+			ID:      testhelper.MkID("parse, semver from a manifest"),
+			cmdName: "parse",
+			args:    []string{"-semver-from", testManifest},
+			expOut: "VERSION=v1.4.0-rc.2\n" +
+				"VERSION_CORE=1.4.0\n" +
+				"VERSION_MAJOR=1\n" +
+				"VERSION_MINOR=4\n" +
+				"VERSION_PATCH=0\n" +
+				"VERSION_PRERELEASE=rc.2\n" +
+				"VERSION_BUILD=\n" +
+				"VERSION_IS_PRERELEASE=true\n",
+		},
+		{
+			ID:      testhelper.MkID("parse, semver and semver-from"),
+			cmdName: "parse",
+			args:    []string{"-semver", "v1.2.3", "-semver-from", testManifest},
+			expErrs: true,
+		},
+		{
+			ID:      testhelper.MkID("parse, no semver"),
+			cmdName: "parse",
+			expErrs: true,
+		},
+		{
+			ID:      testhelper.MkID("validate, good"),
+			cmdName: "validate",
+			args: []string{
+				"-semver", "v1.2.3-rc.1",
+				"-pre-rel-ID-checks", "Length(EQ(2))",
+			},
+		},
+		{
+			ID:      testhelper.MkID("validate, bad"),
+			cmdName: "validate",
+			args: []string{
+				"-semver", "v1.2.3-rc.1",
+				"-pre-rel-ID-checks", "Length(EQ(3))",
+			},
+			expErrs: true,
+		},
+		{
+			ID:      testhelper.MkID("compare, less"),
+			cmdName: "compare",
+			args:    []string{"-semver", "v1.2.3", "-other-semver", "v1.10.0"},
+			expOut:  "-1\n",
+		},
+		{
+			ID:      testhelper.MkID("compare, equal, build IDs ignored"),
+			cmdName: "compare",
+			args:    []string{"-semver", "v1.2.3+a", "-other-semver", "v1.2.3"},
+			expOut:  "0\n",
+		},
+		{
+			ID:      testhelper.MkID("compare, greater"),
+			cmdName: "compare",
+			args: []string{
+				"-semver", "v1.2.3", "-other-semver", "v1.2.3-rc.1",
+			},
+			expOut: "1\n",
+		},
+		{
+			ID:      testhelper.MkID("bump"),
+			cmdName: "bump",
+			args:    []string{"-semver", "v1.2.3-rc.1", "-bump", "minor"},
+			expOut:  "v1.3.0\n",
+		},
+		{
+			ID:      testhelper.MkID("bump, checks applied to the result"),
+			cmdName: "bump",
+			args: []string{
+				"-semver", "v1.2.3-rc.1", "-bump", "minor",
+				"-pre-rel-ID-checks", "Length(EQ(2))",
+			},
+			expErrs: true,
+		},
+		{
+			ID:      testhelper.MkID("bump, checks pass on the result"),
+			cmdName: "bump",
+			args: []string{
+				"-semver", "v1.2.3-rc.1", "-bump", "minor",
+				"-pre-rel-ID-checks", "Length(EQ(0))",
+			},
+			expOut: "v1.3.0\n",
+		},
+		{
+			ID:      testhelper.MkID("bump, no bump given"),
+			cmdName: "bump",
+			args:    []string{"-semver", "v1.2.3"},
+			expErrs: true,
+		},
+		{
+			ID:      testhelper.MkID("format"),
+			cmdName: "format",
+			args:    []string{"-semver", "v1.2.3-rc.1", "-semver-format", "core"},
+			expOut:  "v1.2.3\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		cmd, ok := commands[tc.cmdName]
+		if !ok {
+			t.Fatal("unknown command:", tc.cmdName)
+		}
+
+		p := newProg()
+		ps := paramset.NewNoHelpNoExitNoErrRpt(cmd.params(p)...)
+		ps.Parse(tc.args)
+
+		if errMap := ps.Errors(); (len(errMap) != 0) != tc.expErrs {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected errors: %v", errMap)
+
+			continue
+		}
+
+		if tc.expErrs {
+			continue
+		}
+
+		var b strings.Builder
+
+		status := cmd.run(p, &b)
+		testhelper.DiffInt(t, tc.IDStr(), "exit status", status, exitOK)
+		testhelper.DiffString(t, tc.IDStr(), "output", b.String(), tc.expOut)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var b strings.Builder
+
+	usage(&b)
+
+	for name := range commands {
+		if !strings.Contains(b.String(), "    "+name+" ") {
+			t.Errorf("the usage message does not show the %q command", name)
+		}
+	}
+}
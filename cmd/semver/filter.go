@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nickwells/filecheck.mod/filecheck"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+)
+
+// These are the values that can be given to the keep parameter
+const (
+	keepAll     = "all"
+	keepHighest = "highest"
+	keepLowest  = "lowest"
+)
+
+// These are the values that can be given to the per parameter
+const (
+	perMajor = "major"
+	perMinor = "minor"
+)
+
+// filterOpts holds the values set by the filter parameters
+type filterOpts struct {
+	rng      *semverparams.Range
+	fromFile string
+	keep     string
+	per      string
+}
+
+// lineKey returns the key of the release line of the version. Versions with
+// the same key are in the same release line.
+func (fo filterOpts) lineKey(sv *semver.SV) string {
+	switch fo.per {
+	case perMajor:
+		return fmt.Sprintf("%d", sv.Major())
+	case perMinor:
+		return fmt.Sprintf("%d.%d", sv.Major(), sv.Minor())
+	}
+
+	return ""
+}
+
+// matches returns true if the version is in the range, if any, and passes
+// the checks
+func (fo filterOpts) matches(sv *semver.SV, svCks semverparams.SemverChecks,
+) bool {
+	if fo.rng != nil && !fo.rng.Contains(sv) {
+		return false
+	}
+
+	return svCks.Check(sv) == nil
+}
+
+// filterVersionLines returns the lines whose versions match, in their
+// original order. If only the highest or lowest versions are to be kept
+// then just the first line with the highest or lowest version in each
+// release line is returned.
+func (fo filterOpts) filterVersionLines(
+	vls []versionLine, svCks semverparams.SemverChecks,
+) []versionLine {
+	var matched []versionLine
+
+	for _, vl := range vls {
+		if fo.matches(&vl.sv, svCks) {
+			matched = append(matched, vl)
+		}
+	}
+
+	if fo.keep == keepAll {
+		return matched
+	}
+
+	best := map[string]int{}
+
+	for i, vl := range matched {
+		key := fo.lineKey(&vl.sv)
+
+		j, ok := best[key]
+		if !ok {
+			best[key] = i
+			continue
+		}
+
+		if fo.keep == keepHighest && semver.Less(&matched[j].sv, &vl.sv) ||
+			fo.keep == keepLowest && semver.Less(&vl.sv, &matched[j].sv) {
+			best[key] = i
+		}
+	}
+
+	var kept []versionLine
+
+	for i, vl := range matched {
+		if best[fo.lineKey(&vl.sv)] == i {
+			kept = append(kept, vl)
+		}
+	}
+
+	return kept
+}
+
+// addFilterParams returns a function that will add the parameters for the
+// filter command to the passed PSet
+func addFilterParams(fo *filterOpts) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.Add("range", semverparams.RangeSetter{Value: &fo.rng},
+			"show only the "+semver.Names+" in this range."+
+				" If no range is given all the versions which pass"+
+				" the checks are shown",
+			param.AltNames("rng"),
+		)
+
+		ps.Add("from-file",
+			psetter.Pathname{
+				Value:       &fo.fromFile,
+				Expectation: filecheck.FileExists(),
+			},
+			"read the "+semver.Names+" from this file rather than"+
+				" the standard input",
+		)
+
+		ps.Add("keep",
+			psetter.Enum[string]{
+				Value: &fo.keep,
+				AllowedVals: psetter.AllowedVals[string]{
+					keepAll:     "show every matching version",
+					keepHighest: "show only the highest matching version",
+					keepLowest:  "show only the lowest matching version",
+				},
+			},
+			"which of the matching "+semver.Names+" to show",
+			param.SeeAlso("per"),
+		)
+
+		ps.Add("per",
+			psetter.Enum[string]{
+				Value: &fo.per,
+				AllowedVals: psetter.AllowedVals[string]{
+					perMajor: "for each major version",
+					perMinor: "for each major and minor version",
+				},
+				AllowInvalidInitialValue: true,
+			},
+			"show the highest or lowest matching "+semver.Name+
+				" for each release line rather than just one overall",
+			param.SeeAlso("keep"),
+		)
+
+		return nil
+	}
+}
+
+// runFilter reads semantic version numbers, one per line, from the
+// standard input or a file and writes those in the range which pass the
+// checks. Lines which do not hold a semantic version number are ignored.
+func runFilter(p *prog, w io.Writer) int {
+	r := p.stdin
+
+	if p.filterOpts.fromFile != "" {
+		f, err := os.Open(p.filterOpts.fromFile)
+		if err != nil {
+			return reportErr(err)
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	vls, _, err := readVersionLines(r, p.tagPrefix)
+	if err != nil {
+		return reportErr(err)
+	}
+
+	for _, vl := range p.filterOpts.filterVersionLines(vls, p.svCks) {
+		fmt.Fprintln(w, vl.text)
+	}
+
+	return exitOK
+}
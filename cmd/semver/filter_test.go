@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFilter(t *testing.T) {
+	const input = "v1.4.0\n" +
+		"v1.4.2\n" +
+		"v1.5.0-rc.1\n" +
+		"svc/v1.5.1\n" +
+		"v2.0.0\n" +
+		"not a version\n" +
+		"v1.5.0\n"
+
+	fromFile := filepath.Join(t.TempDir(), "versions.txt")
+
+	err := os.WriteFile(fromFile, []byte("v0.9.0\nv1.0.0\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		args   []string
+		expOut []string
+	}{
+		{
+			ID:   testhelper.MkID("no range"),
+			args: []string{},
+			expOut: []string{
+				"v1.4.0", "v1.4.2", "v1.5.0-rc.1", "v2.0.0", "v1.5.0",
+			},
+		},
+		{
+			ID:   testhelper.MkID("range"),
+			args: []string{"-range", "^v1.4", "-tag-prefix", "svc"},
+			expOut: []string{
+				"v1.4.0", "v1.4.2", "v1.5.0-rc.1", "svc/v1.5.1", "v1.5.0",
+			},
+		},
+		{
+			ID: testhelper.MkID("range, checks"),
+			args: []string{
+				"-range", ">=v1.5.0-0", "-pre-rel-ID-checks", "Length(EQ(2))",
+			},
+			expOut: []string{"v1.5.0-rc.1"},
+		},
+		{
+			ID: testhelper.MkID("highest per minor"),
+			args: []string{
+				"-range", "v1", "-tag-prefix", "svc",
+				"-keep", "highest", "-per", "minor",
+			},
+			expOut: []string{"v1.4.2", "svc/v1.5.1"},
+		},
+		{
+			ID:     testhelper.MkID("lowest per major"),
+			args:   []string{"-keep", "lowest", "-per", "major"},
+			expOut: []string{"v1.4.0", "v2.0.0"},
+		},
+		{
+			ID:     testhelper.MkID("highest overall"),
+			args:   []string{"-keep", "highest", "-range", "<v2"},
+			expOut: []string{"v1.5.0"},
+		},
+		{
+			ID:     testhelper.MkID("from a file"),
+			args:   []string{"-from-file", fromFile, "-range", "v1"},
+			expOut: []string{"v1.0.0"},
+		},
+	}
+
+	for _, tc := range testCases {
+		p := newProg()
+		p.stdin = strings.NewReader(input)
+
+		ps := paramset.NewNoHelpNoExitNoErrRpt(commands["filter"].params(p)...)
+		ps.Parse(tc.args)
+
+		if errMap := ps.Errors(); len(errMap) != 0 {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected errors: %v", errMap)
+
+			continue
+		}
+
+		var b strings.Builder
+
+		status := runFilter(p, &b)
+		testhelper.DiffInt(t, tc.IDStr(), "exit status", status, exitOK)
+		testhelper.DiffStringSlice(t, tc.IDStr(), "output",
+			strings.Fields(b.String()), tc.expOut)
+	}
+}
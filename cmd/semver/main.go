@@ -0,0 +1,94 @@
+/*
+The semver program works with semantic version numbers. It offers a number
+of commands, given as the first argument, each with its own parameters:
+
+	semver parse -semver v1.2.3-rc.1
+	semver validate -semver v1.2.3-rc.1 -pre-rel-ID-checks 'Length(EQ(2))'
+	semver compare -semver v1.2.3 -other-semver v1.10.0
+	semver bump -semver v1.2.3 -bump minor
+	semver format -semver v1.2.3 -semver-format core
+	git tag | semver sort -reverse -no-pre-rel -bad-lines pass
+	git tag | semver filter -range '^v1.4' -keep highest -per minor
+	semver audit-tags -output json -fail-on warning
+
+Use 'semver COMMAND -help' to see the parameters for a command. The checks
+on pre-release and build IDs can be given in the semver-checks group config
+file, they are applied by the validate, bump, filter and audit-tags
+commands.
+*/
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+)
+
+// These are the exit statuses of the program
+const (
+	exitOK    = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+
+	name := os.Args[1]
+
+	switch name {
+	case "help", "-help", "--help", "-h":
+		usage(os.Stdout)
+		os.Exit(exitOK)
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command: %q\n\n", name)
+		usage(os.Stderr)
+		os.Exit(exitUsage)
+	}
+
+	// The command name is made part of the program name so that it appears
+	// in the help and error messages
+	os.Args = append([]string{os.Args[0] + " " + name}, os.Args[2:]...)
+
+	p := newProg()
+	ps := paramset.New(cmd.params(p)...)
+	ps.Parse()
+
+	os.Exit(cmd.run(p, os.Stdout))
+}
+
+// usage writes the available commands to the writer
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: semver COMMAND [parameters]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The commands are:")
+
+	names := make([]string, 0, len(commands))
+	width := 0
+
+	for name := range commands {
+		names = append(names, name)
+		width = max(width, len(name))
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-*s  %s\n", width, name, commands[name].desc)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w,
+		"Use 'semver COMMAND -help' to see the parameters for a command.")
+	fmt.Fprintln(w, "The checks on pre-release and build IDs can be given"+
+		" in the semver-checks group config file.")
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// These are the values that can be given to the bad-lines parameter
+const (
+	badLinesReject = "reject"
+	badLinesPass   = "pass"
+)
+
+// sortOpts holds the values set by the sort parameters
+type sortOpts struct {
+	reverse       bool
+	unique        bool
+	ignoreBuild   bool
+	excludePreRel bool
+	badLines      string
+}
+
+// compare returns -1, 0 or 1 as a is ordered before, with or after b. The
+// versions are ordered by precedence, any build IDs are compared as text
+// unless they are to be ignored.
+func (so sortOpts) compare(a, b versionLine) int {
+	cmp := 0
+
+	switch {
+	case semver.Less(&a.sv, &b.sv):
+		cmp = -1
+	case semver.Less(&b.sv, &a.sv):
+		cmp = 1
+	case !so.ignoreBuild:
+		cmp = strings.Compare(
+			strings.Join(a.sv.BuildIDs(), "."),
+			strings.Join(b.sv.BuildIDs(), "."))
+	}
+
+	if so.reverse {
+		return -cmp
+	}
+
+	return cmp
+}
+
+// sortVersionLines returns the lines, less any pre-releases if they are to
+// be excluded, sorted as given by the sort options and with any duplicates
+// removed if they are to be unique.
+func (so sortOpts) sortVersionLines(vls []versionLine) []versionLine {
+	if so.excludePreRel {
+		vls = slices.DeleteFunc(vls, func(vl versionLine) bool {
+			return vl.sv.HasPreRelIDs()
+		})
+	}
+
+	slices.SortStableFunc(vls, so.compare)
+
+	if so.unique {
+		vls = slices.CompactFunc(vls, func(a, b versionLine) bool {
+			return so.compare(a, b) == 0
+		})
+	}
+
+	return vls
+}
+
+// addSortParams returns a function that will add the parameters for the
+// sort command to the passed PSet
+func addSortParams(so *sortOpts) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.Add("reverse", psetter.Bool{Value: &so.reverse},
+			"sort in descending order of precedence",
+			param.AltNames("r"),
+		)
+
+		ps.Add("unique", psetter.Bool{Value: &so.unique},
+			"show only the first of any lines with the same "+semver.Name,
+			param.AltNames("u"),
+			param.SeeAlso("ignore-build"),
+		)
+
+		ps.Add("ignore-build", psetter.Bool{Value: &so.ignoreBuild},
+			"ignore any build IDs when ordering the "+semver.Names+
+				" and when finding duplicates. Otherwise versions of"+
+				" equal precedence are ordered by their build IDs",
+		)
+
+		ps.Add("no-pre-rel", psetter.Bool{Value: &so.excludePreRel},
+			"exclude any "+semver.Names+" having pre-release IDs",
+		)
+
+		ps.Add("bad-lines",
+			psetter.Enum[string]{
+				Value: &so.badLines,
+				AllowedVals: psetter.AllowedVals[string]{
+					badLinesReject: "report the lines as errors" +
+						" and show nothing else",
+					badLinesPass: "show the lines, unchanged," +
+						" after the sorted lines",
+				},
+			},
+			"what to do with lines which do not hold a "+semver.Name,
+		)
+
+		return nil
+	}
+}
+
+// runSort reads semantic version numbers, one per line, from the standard
+// input and writes them sorted by precedence
+func runSort(p *prog, w io.Writer) int {
+	vls, bls, err := readVersionLines(p.stdin, p.tagPrefix)
+	if err != nil {
+		return reportErr(err)
+	}
+
+	if len(bls) > 0 && p.sortOpts.badLines == badLinesReject {
+		return reportBadLines(bls)
+	}
+
+	for _, vl := range p.sortOpts.sortVersionLines(vls) {
+		fmt.Fprintln(w, vl.text)
+	}
+
+	for _, bl := range bls {
+		fmt.Fprintln(w, bl.text)
+	}
+
+	return exitOK
+}
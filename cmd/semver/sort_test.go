@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSort(t *testing.T) {
+	const input = "v1.2.3\n" +
+		"svc/v1.10.0\n" +
+		"\n" +
+		"v1.2.3-rc.1\n" +
+		"  v1.2.3+b  \n" +
+		"v1.2.3+a\n" +
+		"not a version\n" +
+		"v0.1.0\n"
+
+	testCases := []struct {
+		testhelper.ID
+		args      []string
+		expStatus int
+		expOut    []string
+	}{
+		{
+			ID:        testhelper.MkID("bad lines rejected by default"),
+			args:      []string{"-tag-prefix", "svc"},
+			expStatus: exitFail,
+		},
+		{
+			ID:   testhelper.MkID("bad lines passed through"),
+			args: []string{"-tag-prefix", "svc", "-bad-lines", "pass"},
+			expOut: []string{
+				"v0.1.0",
+				"v1.2.3-rc.1",
+				"v1.2.3",
+				"v1.2.3+a",
+				"v1.2.3+b",
+				"svc/v1.10.0",
+				"not a version",
+			},
+		},
+		{
+			ID: testhelper.MkID("no tag prefix"),
+			args: []string{
+				"-bad-lines", "pass", "-no-pre-rel",
+			},
+			expOut: []string{
+				"v0.1.0",
+				"v1.2.3",
+				"v1.2.3+a",
+				"v1.2.3+b",
+				"svc/v1.10.0",
+				"not a version",
+			},
+		},
+		{
+			ID: testhelper.MkID("reverse, unique"),
+			args: []string{
+				"-tag-prefix", "svc", "-bad-lines", "pass",
+				"-reverse", "-unique",
+			},
+			expOut: []string{
+				"svc/v1.10.0",
+				"v1.2.3+b",
+				"v1.2.3+a",
+				"v1.2.3",
+				"v1.2.3-rc.1",
+				"v0.1.0",
+				"not a version",
+			},
+		},
+		{
+			ID: testhelper.MkID("unique, ignoring build IDs"),
+			args: []string{
+				"-tag-prefix", "svc", "-bad-lines", "pass",
+				"-unique", "-ignore-build", "-no-pre-rel",
+			},
+			expOut: []string{
+				"v0.1.0",
+				"v1.2.3",
+				"svc/v1.10.0",
+				"not a version",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		p := newProg()
+		p.stdin = strings.NewReader(input)
+
+		ps := paramset.NewNoHelpNoExitNoErrRpt(commands["sort"].params(p)...)
+		ps.Parse(tc.args)
+
+		if errMap := ps.Errors(); len(errMap) != 0 {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected errors: %v", errMap)
+
+			continue
+		}
+
+		var b strings.Builder
+
+		status := runSort(p, &b)
+		testhelper.DiffInt(t, tc.IDStr(), "exit status", status, tc.expStatus)
+
+		var out []string
+
+		if b.Len() > 0 {
+			out = strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "output", out, tc.expOut)
+	}
+}
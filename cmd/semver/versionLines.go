@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+)
+
+// versionLine is a line of input holding a semantic version number
+type versionLine struct {
+	text string
+	sv   semver.SV
+}
+
+// badLine is a line of input which could not be parsed
+type badLine struct {
+	lineNum int
+	text    string
+	err     error
+}
+
+// String returns a description of the bad line suitable for an error
+// message
+func (bl badLine) String() string {
+	return fmt.Sprintf("line %d: %q: %s", bl.lineNum, bl.text, bl.err)
+}
+
+// readVersionLines reads lines from the reader and parses them using the
+// same rules as the semver parameter. Leading and trailing white space is
+// removed and blank lines are ignored. It returns the lines holding
+// semantic version numbers and those which could not be parsed.
+func readVersionLines(r io.Reader, tagPrefix string,
+) ([]versionLine, []badLine, error) {
+	var (
+		vls []versionLine
+		bls []badLine
+	)
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		vl := versionLine{text: text}
+
+		err := semverparams.SVSetter{Value: &vl.sv, TagPrefix: tagPrefix}.
+			SetWithVal("", text)
+		if err != nil {
+			bls = append(bls, badLine{lineNum: lineNum, text: text, err: err})
+			continue
+		}
+
+		vls = append(vls, vl)
+	}
+
+	return vls, bls, scanner.Err()
+}
+
+// reportBadLines reports the lines which could not be parsed and returns
+// the failure exit status
+func reportBadLines(bls []badLine) int {
+	fmt.Fprintf(os.Stderr, "Error: %d bad %s:\n",
+		len(bls), plural(len(bls), "line", "lines"))
+
+	for _, bl := range bls {
+		fmt.Fprintln(os.Stderr, "\t"+bl.String())
+	}
+
+	return exitFail
+}
+
+// plural returns the singular form if n is 1 and the plural form otherwise
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return singular
+	}
+
+	return pluralForm
+}
+
+// addTagPrefixParam returns a function that will add the parameter for
+// setting the tag prefix used when reading lines of semantic version
+// numbers to the passed PSet
+func addTagPrefixParam(tagPrefix *string) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.Add("tag-prefix", psetter.String[string]{Value: tagPrefix},
+			"the directory part of tag names, such as 'services/billing'"+
+				" for tags like 'services/billing/v1.4.0'. Lines may"+
+				" then hold either plain "+semver.Names+" or tag names"+
+				" starting with this prefix",
+		)
+
+		return nil
+	}
+}
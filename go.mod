@@ -5,6 +5,7 @@ require (
 	github.com/nickwells/checksetter.mod/v4 v4.0.34
 	github.com/nickwells/errutil.mod v1.2.24
 	github.com/nickwells/filecheck.mod v1.2.13
+	github.com/nickwells/location.mod v1.2.37
 	github.com/nickwells/param.mod/v7 v7.2.2
 	github.com/nickwells/semver.mod/v3 v3.2.3
 	github.com/nickwells/testhelper.mod/v2 v2.6.1
@@ -19,7 +20,6 @@ require (
 require (
 	github.com/nickwells/english.mod v1.2.10 // indirect
 	github.com/nickwells/fileparse.mod v1.1.39 // indirect
-	github.com/nickwells/location.mod v1.2.37 // indirect
 	github.com/nickwells/mathutil.mod/v2 v2.5.11 // indirect
 	github.com/nickwells/pager.mod v1.1.0 // indirect
 	github.com/nickwells/tempus.mod v1.2.11 // indirect
@@ -0,0 +1,230 @@
+package gitrepo
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit holds the details of a commit object
+type Commit struct {
+	Hash    Hash
+	Tree    Hash
+	Parents []Hash
+	// Author and Committer are the name and email of the author and
+	// committer, without the timestamp
+	Author    string
+	Committer string
+	// Time is the commit time
+	Time    time.Time
+	Message string
+}
+
+// Subject returns the first line of the commit message
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// ReadCommit reads and parses the named commit
+func (r *Repo) ReadCommit(h Hash) (*Commit, error) {
+	ot, data, err := r.ReadObject(h)
+	if err != nil {
+		return nil, err
+	}
+
+	if ot != ObjCommit {
+		return nil, fmt.Errorf("object %s is a %s not a commit", h, ot)
+	}
+
+	c, err := parseCommit(data)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", h, err)
+	}
+
+	c.Hash = h
+
+	return c, nil
+}
+
+// parseCommit parses the content of a commit object
+func parseCommit(data []byte) (*Commit, error) {
+	c := &Commit{}
+
+	header, msg, _ := bytes.Cut(data, []byte("\n\n"))
+	c.Message = string(msg)
+
+	for line := range strings.SplitSeq(string(header), "\n") {
+		key, val, _ := strings.Cut(line, " ")
+
+		var err error
+
+		switch key {
+		case "tree":
+			c.Tree, err = ParseHash(val)
+		case "parent":
+			var p Hash
+
+			p, err = ParseHash(val)
+			c.Parents = append(c.Parents, p)
+		case "author":
+			c.Author, _ = splitSignature(val)
+		case "committer":
+			c.Committer, c.Time = splitSignature(val)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// splitSignature splits an author or committer line into the name and
+// email part and the time
+func splitSignature(sig string) (string, time.Time) {
+	end := strings.LastIndexByte(sig, '>')
+	if end < 0 {
+		return sig, time.Time{}
+	}
+
+	who := sig[:end+1]
+
+	fields := strings.Fields(sig[end+1:])
+	if len(fields) == 0 {
+		return who, time.Time{}
+	}
+
+	secs, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return who, time.Time{}
+	}
+
+	t := time.Unix(secs, 0)
+
+	const tzLen = 5 // +hhmm
+	if len(fields) > 1 && len(fields[1]) == tzLen {
+		hh, errH := strconv.Atoi(fields[1][1:3])
+		mm, errM := strconv.Atoi(fields[1][3:])
+
+		if errH == nil && errM == nil {
+			offset := hh*60*60 + mm*60
+			if fields[1][0] == '-' {
+				offset = -offset
+			}
+
+			t = t.In(time.FixedZone(fields[1], offset))
+		}
+	}
+
+	return who, t
+}
+
+// parents returns the parents of the commit which are in the repository;
+// a commit at the boundary of a shallow clone is treated as having no
+// parents
+func (r *Repo) parents(c *Commit) ([]Hash, error) {
+	shallow, err := r.isShallow(c.Hash)
+	if err != nil || shallow {
+		return nil, err
+	}
+
+	return c.Parents, nil
+}
+
+// ancestors returns the set of commits reachable from the given commit
+// (including the commit itself)
+func (r *Repo) ancestors(h Hash) (map[Hash]bool, error) {
+	seen := map[Hash]bool{}
+	pending := []Hash{h}
+
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if seen[h] {
+			continue
+		}
+
+		seen[h] = true
+
+		c, err := r.ReadCommit(h)
+		if err != nil {
+			return nil, err
+		}
+
+		parents, err := r.parents(c)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = append(pending, parents...)
+	}
+
+	return seen, nil
+}
+
+// CommitsBetween returns the commits reachable from the 'to' revision but
+// not from the 'from' revision, as for 'git log from..to'. If 'from' is
+// empty all the commits reachable from 'to' are returned. The commits are
+// returned newest first. In a shallow clone the history stops at the
+// commits whose parents are missing.
+func (r *Repo) CommitsBetween(from, to string) ([]*Commit, error) {
+	excluded := map[Hash]bool{}
+
+	if from != "" {
+		fromHash, err := r.Resolve(from)
+		if err != nil {
+			return nil, err
+		}
+
+		excluded, err = r.ancestors(fromHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	toHash, err := r.Resolve(to)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []*Commit
+
+	pending := []Hash{toHash}
+
+	for len(pending) > 0 {
+		h := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if excluded[h] {
+			continue
+		}
+
+		excluded[h] = true
+
+		c, err := r.ReadCommit(h)
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, c)
+
+		parents, err := r.parents(c)
+		if err != nil {
+			return nil, err
+		}
+
+		pending = append(pending, parents...)
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Time.After(commits[j].Time)
+	})
+
+	return commits, nil
+}
@@ -0,0 +1,155 @@
+package gitrepo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ObjType is the type of a git object
+type ObjType int
+
+// These are the git object types. The values are those used in pack files.
+const (
+	ObjCommit ObjType = 1
+	ObjTree   ObjType = 2
+	ObjBlob   ObjType = 3
+	ObjTag    ObjType = 4
+)
+
+// String returns the name of the object type as used by git
+func (ot ObjType) String() string {
+	switch ot {
+	case ObjCommit:
+		return "commit"
+	case ObjTree:
+		return "tree"
+	case ObjBlob:
+		return "blob"
+	case ObjTag:
+		return "tag"
+	}
+
+	return "unknown object type (" + strconv.Itoa(int(ot)) + ")"
+}
+
+// objTypeByName maps the names of the object types to their values
+var objTypeByName = map[string]ObjType{
+	"commit": ObjCommit,
+	"tree":   ObjTree,
+	"blob":   ObjBlob,
+	"tag":    ObjTag,
+}
+
+// ReadObject returns the type and content of the named object. The object
+// is looked for first as a loose object and then in the pack files.
+func (r *Repo) ReadObject(h Hash) (ObjType, []byte, error) {
+	ot, data, err := r.readLooseObject(h)
+	if err == nil || !errors.Is(err, ErrNotFound) {
+		return ot, data, err
+	}
+
+	packs, err := r.loadPacks()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	for _, p := range packs {
+		if offset, ok := p.idx.find(h); ok {
+			return p.readObjectAt(r, offset)
+		}
+	}
+
+	return 0, nil, fmt.Errorf("object %s: %w", h, ErrNotFound)
+}
+
+// readLooseObject reads the named object from the objects directory
+func (r *Repo) readLooseObject(h Hash) (ObjType, []byte, error) {
+	name := h.String()
+
+	f, err := os.Open(
+		filepath.Join(r.commonDir, "objects", name[:2], name[2:]))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil, fmt.Errorf("object %s: %w", h, ErrNotFound)
+		}
+
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %s: %w", h, err)
+	}
+	defer zr.Close()
+
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("object %s: %w", h, err)
+	}
+
+	header, data, ok := bytes.Cut(content, []byte{0})
+	if !ok {
+		return 0, nil, fmt.Errorf("object %s: no header", h)
+	}
+
+	typeName, sizeStr, _ := bytes.Cut(header, []byte(" "))
+
+	ot, ok := objTypeByName[string(typeName)]
+	if !ok {
+		return 0, nil, fmt.Errorf("object %s: unknown type %q", h, typeName)
+	}
+
+	if size, err := strconv.Atoi(string(sizeStr)); err != nil ||
+		size != len(data) {
+		return 0, nil, fmt.Errorf("object %s: bad size %q", h, sizeStr)
+	}
+
+	return ot, data, nil
+}
+
+// Peel follows any annotated tags and returns the name of the first object
+// which is not a tag.
+func (r *Repo) Peel(h Hash) (Hash, error) {
+	for {
+		ot, data, err := r.ReadObject(h)
+		if err != nil {
+			return Hash{}, err
+		}
+
+		if ot != ObjTag {
+			return h, nil
+		}
+
+		tag := h
+
+		h, err = headerHash(data, "object")
+		if err != nil {
+			return Hash{}, fmt.Errorf("tag object %s: %w", tag, err)
+		}
+	}
+}
+
+// headerHash finds the first header line with the given key in the commit
+// or tag object data and returns the object name it gives
+func headerHash(data []byte, key string) (Hash, error) {
+	for line := range bytes.Lines(data) {
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		if len(line) == 0 {
+			break
+		}
+
+		if v, ok := bytes.CutPrefix(line, []byte(key+" ")); ok {
+			return ParseHash(string(v))
+		}
+	}
+
+	return Hash{}, fmt.Errorf("no %q header", key)
+}
@@ -0,0 +1,375 @@
+package gitrepo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// These are the object types which only appear in pack files
+const (
+	objOfsDelta ObjType = 6
+	objRefDelta ObjType = 7
+)
+
+// maxDeltaPrealloc is the largest space allocated for the result of a
+// delta before it is applied, any more is allocated as it is needed
+const maxDeltaPrealloc = 1 << 20
+
+// maxDeltaDepth is the maximum length of a chain of deltas that will be
+// followed when reading an object from a pack file
+const maxDeltaDepth = 1000
+
+// packIdx holds the contents of a version 2 pack index file
+type packIdx struct {
+	names   []Hash
+	offsets []int64
+}
+
+// find returns the offset in the pack file of the named object
+func (pi packIdx) find(h Hash) (int64, bool) {
+	i := sort.Search(len(pi.names), func(i int) bool {
+		return bytes.Compare(pi.names[i][:], h[:]) >= 0
+	})
+
+	if i < len(pi.names) && pi.names[i] == h {
+		return pi.offsets[i], true
+	}
+
+	return 0, false
+}
+
+// pack records a pack file and its index
+type pack struct {
+	filename string
+	idx      packIdx
+}
+
+// loadPacks reads the index files of all the packs in the repository
+func (r *Repo) loadPacks() ([]*pack, error) {
+	if r.packsLoaded {
+		return r.packs, nil
+	}
+
+	idxFiles, err := filepath.Glob(
+		filepath.Join(r.commonDir, "objects", "pack", "*.idx"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, idxFile := range idxFiles {
+		idx, err := readPackIdx(idxFile)
+		if err != nil {
+			return nil, err
+		}
+
+		r.packs = append(r.packs, &pack{
+			filename: strings.TrimSuffix(idxFile, ".idx") + ".pack",
+			idx:      idx,
+		})
+	}
+
+	r.packsLoaded = true
+
+	return r.packs, nil
+}
+
+// readPackIdx reads a version 2 pack index file
+func readPackIdx(filename string) (packIdx, error) {
+	content, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return packIdx{}, err
+	}
+
+	const (
+		headerLen  = 8
+		fanoutLen  = 256 * 4
+		crcLen     = 4
+		offsetLen  = 4
+		largeOfLen = 8
+		largeOfBit = 0x80000000
+	)
+
+	if len(content) < headerLen+fanoutLen ||
+		!bytes.Equal(content[:headerLen],
+			[]byte{0xff, 't', 'O', 'c', 0, 0, 0, 2}) {
+		return packIdx{},
+			fmt.Errorf("%s: not a version 2 pack index", filename)
+	}
+
+	count := int(binary.BigEndian.Uint32(content[headerLen+fanoutLen-4:]))
+
+	namesStart := headerLen + fanoutLen
+	offsetsStart := namesStart + count*HashLen + count*crcLen
+	largeStart := offsetsStart + count*offsetLen
+
+	if len(content) < largeStart {
+		return packIdx{},
+			fmt.Errorf("%s: the pack index is truncated", filename)
+	}
+
+	pi := packIdx{
+		names:   make([]Hash, count),
+		offsets: make([]int64, count),
+	}
+
+	for i := range count {
+		copy(pi.names[i][:], content[namesStart+i*HashLen:])
+
+		off := binary.BigEndian.Uint32(content[offsetsStart+i*offsetLen:])
+		if off&largeOfBit == 0 {
+			pi.offsets[i] = int64(off)
+			continue
+		}
+
+		largeIdx := largeStart + int(off&^largeOfBit)*largeOfLen
+		if len(content) < largeIdx+largeOfLen {
+			return packIdx{}, fmt.Errorf("%s: bad large offset", filename)
+		}
+
+		pi.offsets[i] = int64( //nolint:gosec
+			binary.BigEndian.Uint64(content[largeIdx:]))
+	}
+
+	return pi, nil
+}
+
+// readObjectAt reads the object at the given offset in the pack file,
+// applying any deltas
+func (p *pack) readObjectAt(r *Repo, offset int64) (ObjType, []byte, error) {
+	f, err := os.Open(p.filename)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	var deltas [][]byte
+
+	for range maxDeltaDepth {
+		ot, data, baseOffset, baseHash, err := readPackEntry(f, offset)
+		if err != nil {
+			return 0, nil, fmt.Errorf("%s: offset %d: %w",
+				p.filename, offset, err)
+		}
+
+		switch ot {
+		case objOfsDelta:
+			deltas = append(deltas, data)
+			offset = baseOffset
+
+			continue
+		case objRefDelta:
+			deltas = append(deltas, data)
+
+			ot, data, err = r.ReadObject(baseHash)
+			if err != nil {
+				return 0, nil, err
+			}
+		}
+
+		for i := len(deltas) - 1; i >= 0; i-- {
+			data, err = applyDelta(data, deltas[i])
+			if err != nil {
+				return 0, nil, fmt.Errorf("%s: offset %d: %w",
+					p.filename, offset, err)
+			}
+		}
+
+		return ot, data, nil
+	}
+
+	return 0, nil, fmt.Errorf("%s: the delta chain is too long", p.filename)
+}
+
+// readPackEntry reads the entry at the given offset in the pack file. It
+// returns the entry type and its decompressed data. For an offset delta it
+// also returns the offset of the base object and for a reference delta the
+// name of the base object.
+func readPackEntry(f *os.File, offset int64) (
+	ObjType, []byte, int64, Hash, error,
+) {
+	br := bufio.NewReader(io.NewSectionReader(f, offset, 1<<62))
+
+	c, err := br.ReadByte()
+	if err != nil {
+		return 0, nil, 0, Hash{}, err
+	}
+
+	ot := ObjType((c >> 4) & 0x7)
+	size := int64(c & 0xf)
+
+	for shift := 4; c&0x80 != 0; shift += 7 {
+		if c, err = br.ReadByte(); err != nil {
+			return 0, nil, 0, Hash{}, err
+		}
+
+		size |= int64(c&0x7f) << shift
+	}
+
+	var baseOffset int64
+
+	var baseHash Hash
+
+	switch ot {
+	case objOfsDelta:
+		rel, err := readOfsDeltaOffset(br)
+		if err != nil {
+			return 0, nil, 0, Hash{}, err
+		}
+
+		baseOffset = offset - rel
+	case objRefDelta:
+		if _, err := io.ReadFull(br, baseHash[:]); err != nil {
+			return 0, nil, 0, Hash{}, err
+		}
+	}
+
+	zr, err := zlib.NewReader(br)
+	if err != nil {
+		return 0, nil, 0, Hash{}, err
+	}
+	defer zr.Close()
+
+	// The size is not trusted for the allocation, the data is read until
+	// it exceeds the size so that a corrupt entry cannot force a huge
+	// allocation
+	data, err := io.ReadAll(io.LimitReader(zr, size+1))
+	if err != nil {
+		return 0, nil, 0, Hash{}, err
+	}
+
+	if int64(len(data)) != size {
+		return 0, nil, 0, Hash{}, fmt.Errorf(
+			"the entry size is %d, expected %d", len(data), size)
+	}
+
+	return ot, data, baseOffset, baseHash, nil
+}
+
+// readOfsDeltaOffset reads the relative offset of the base object of an
+// offset delta
+func readOfsDeltaOffset(br io.ByteReader) (int64, error) {
+	c, err := br.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	rel := int64(c & 0x7f)
+
+	for c&0x80 != 0 {
+		if c, err = br.ReadByte(); err != nil {
+			return 0, err
+		}
+
+		rel = ((rel + 1) << 7) | int64(c&0x7f)
+	}
+
+	return rel, nil
+}
+
+// errBadDelta is returned if a delta cannot be applied
+var errBadDelta = errors.New("bad delta")
+
+// deltaSize reads a size from the header of a delta
+func deltaSize(delta []byte) (int, []byte, error) {
+	size := 0
+
+	for shift := 0; ; shift += 7 {
+		if len(delta) == 0 {
+			return 0, nil, errBadDelta
+		}
+
+		c := delta[0]
+		delta = delta[1:]
+		size |= int(c&0x7f) << shift
+
+		if c&0x80 == 0 {
+			return size, delta, nil
+		}
+	}
+}
+
+// applyDelta applies the delta to the base data and returns the result
+func applyDelta(base, delta []byte) ([]byte, error) {
+	srcSize, delta, err := deltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+
+	if srcSize != len(base) {
+		return nil, fmt.Errorf("%w: the base is the wrong size", errBadDelta)
+	}
+
+	dstSize, delta, err := deltaSize(delta)
+	if err != nil {
+		return nil, err
+	}
+
+	// the size in the delta header is not trusted for the allocation
+	result := make([]byte, 0, min(dstSize, maxDeltaPrealloc))
+
+	for len(delta) > 0 {
+		if len(result) > dstSize {
+			return nil, fmt.Errorf("%w: the result is too big", errBadDelta)
+		}
+
+		op := delta[0]
+		delta = delta[1:]
+
+		if op&0x80 == 0 {
+			n := int(op)
+			if n == 0 || n > len(delta) {
+				return nil, errBadDelta
+			}
+
+			result = append(result, delta[:n]...)
+			delta = delta[n:]
+
+			continue
+		}
+
+		var cpOffset, cpSize int
+
+		for i := range 7 {
+			if op&(1<<i) == 0 {
+				continue
+			}
+
+			if len(delta) == 0 {
+				return nil, errBadDelta
+			}
+
+			if i < 4 {
+				cpOffset |= int(delta[0]) << (8 * i)
+			} else {
+				cpSize |= int(delta[0]) << (8 * (i - 4))
+			}
+
+			delta = delta[1:]
+		}
+
+		if cpSize == 0 {
+			cpSize = 0x10000
+		}
+
+		if cpOffset+cpSize > len(base) {
+			return nil, errBadDelta
+		}
+
+		result = append(result, base[cpOffset:cpOffset+cpSize]...)
+	}
+
+	if len(result) != dstSize {
+		return nil, fmt.Errorf("%w: the result is the wrong size", errBadDelta)
+	}
+
+	return result, nil
+}
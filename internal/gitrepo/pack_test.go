@@ -0,0 +1,74 @@
+package gitrepo
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadPackEntryBadSize(t *testing.T) {
+	// a blob entry header claiming a size of 2^38 bytes
+	entry := []byte{0xb0, 0x80, 0x80, 0x80, 0x80, 0x40}
+
+	var z bytes.Buffer
+
+	zw := zlib.NewWriter(&z)
+	_, _ = zw.Write([]byte("abc"))
+	_ = zw.Close()
+
+	entry = append(entry, z.Bytes()...)
+
+	name := filepath.Join(t.TempDir(), "bad.pack")
+	if err := os.WriteFile(name, entry, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	_, _, _, _, err = readPackEntry(f, 0)
+	testhelper.CheckExpErrWithID(t, "bad entry size", err,
+		testhelper.MkExpErr("the entry size is 3, expected 274877906944"))
+}
+
+func TestApplyDeltaBadSize(t *testing.T) {
+	base := []byte("abc")
+
+	testCases := []struct {
+		testhelper.ID
+		delta  []byte
+		expErr string
+	}{
+		{
+			ID: testhelper.MkID("huge result size"),
+			// source size 3, result size 2^35, insert "x"
+			delta:  []byte{0x03, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01, 0x01, 'x'},
+			expErr: "the result is the wrong size",
+		},
+		{
+			ID: testhelper.MkID("result bigger than its size"),
+			// source size 3, result size 1, copy 3 bytes twice
+			delta:  []byte{0x03, 0x01, 0x90, 0x03, 0x90, 0x03},
+			expErr: "the result is too big",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := applyDelta(base, tc.delta)
+		if !errors.Is(err, errBadDelta) {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: expected a bad delta error, got: %v", err)
+		}
+
+		testhelper.CheckExpErrWithID(t, tc.IDStr(), err,
+			testhelper.MkExpErr(tc.expErr))
+	}
+}
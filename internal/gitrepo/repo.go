@@ -0,0 +1,450 @@
+/*
+Package gitrepo offers minimal, read-only access to a local git
+repository. It can resolve references, list tags and read commits from both
+loose objects and pack files without needing the git program.
+*/
+package gitrepo
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// HashLen is the length in bytes of an object name (a SHA-1 hash)
+const HashLen = 20
+
+// Hash is the name of a git object
+type Hash [HashLen]byte
+
+// String returns the hash in hexadecimal
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// IsZero returns true if the hash has not been set
+func (h Hash) IsZero() bool {
+	return h == Hash{}
+}
+
+// ParseHash converts a full hexadecimal object name into a Hash
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+
+	if len(s) != hex.EncodedLen(HashLen) {
+		return h, fmt.Errorf("bad object name %q: the wrong length", s)
+	}
+
+	if _, err := hex.Decode(h[:], []byte(s)); err != nil {
+		return h, fmt.Errorf("bad object name %q: %w", s, err)
+	}
+
+	return h, nil
+}
+
+// Repo represents a local git repository
+type Repo struct {
+	// gitDir is the repository directory holding HEAD
+	gitDir string
+	// commonDir is the repository directory holding the objects and refs,
+	// it differs from the gitDir for a linked worktree
+	commonDir string
+
+	packs       []*pack
+	packsLoaded bool
+
+	// shallow holds the commits whose parents are missing from a shallow
+	// clone, see isShallow
+	shallow       map[Hash]bool
+	shallowLoaded bool
+}
+
+// ErrNotFound is returned if a reference or object cannot be found
+var ErrNotFound = errors.New("not found")
+
+// Find searches for a git repository starting at the given directory and
+// moving up through the parent directories. It returns the first one it
+// finds or an error if there is none.
+func Find(dir string) (*Repo, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		r, err := Open(dir)
+		if err == nil {
+			return r, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf(
+				"no git repository found in %q or any parent directory",
+				dir)
+		}
+
+		dir = parent
+	}
+}
+
+// Open opens the git repository in the given directory. The directory can
+// be either a working tree (holding a .git directory or file) or the git
+// directory itself.
+func Open(dir string) (*Repo, error) {
+	gitDir := filepath.Join(dir, ".git")
+
+	info, err := os.Stat(gitDir)
+
+	switch {
+	case err != nil:
+		gitDir = dir
+	case !info.IsDir():
+		gitDir, err = readGitFile(gitDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !isGitDir(gitDir) {
+		return nil, fmt.Errorf("%q is not a git repository", dir)
+	}
+
+	r := &Repo{gitDir: gitDir, commonDir: gitDir}
+
+	if cd, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		r.commonDir = strings.TrimSpace(string(cd))
+		if !filepath.IsAbs(r.commonDir) {
+			r.commonDir = filepath.Join(gitDir, r.commonDir)
+		}
+	}
+
+	return r, nil
+}
+
+// readGitFile reads a .git file (as used for linked worktrees and
+// submodules) and returns the git directory it refers to
+func readGitFile(name string) (string, error) {
+	content, err := os.ReadFile(name) //nolint:gosec
+	if err != nil {
+		return "", err
+	}
+
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(content)),
+		"gitdir: ")
+	if !ok {
+		return "", fmt.Errorf("%q is not a valid .git file", name)
+	}
+
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(name), gitDir)
+	}
+
+	return gitDir, nil
+}
+
+// isGitDir returns true if the directory looks like a git directory
+func isGitDir(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(dir, "objects"))
+	if err == nil {
+		return true
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "commondir"))
+
+	return err == nil
+}
+
+// pseudoRefs are the names of the references held at the top level of the
+// git directory rather than under refs/
+var pseudoRefs = []string{"HEAD", "FETCH_HEAD", "ORIG_HEAD", "MERGE_HEAD"}
+
+// isPseudoRef returns true if the name is one of the pseudoRefs
+func isPseudoRef(name string) bool {
+	return slices.Contains(pseudoRefs, name)
+}
+
+// maxSymRefDepth is the maximum number of symbolic references that will be
+// followed when resolving a reference
+const maxSymRefDepth = 10
+
+// readRef reads the named reference (such as "HEAD" or "refs/tags/v1.0.0")
+// following any symbolic references. It returns ErrNotFound if there is no
+// such reference.
+func (r *Repo) readRef(name string) (Hash, error) {
+	for range maxSymRefDepth {
+		dir := r.commonDir
+		if isPseudoRef(name) {
+			dir = r.gitDir
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		content, err := os.ReadFile(path) //nolint:gosec
+		if err != nil {
+			// a directory (such as refs/tags/a for the tag a/b) is not a
+			// reference but the name may still be in the packed-refs file
+			if !errors.Is(err, fs.ErrNotExist) && !isDir(path) {
+				return Hash{}, err
+			}
+
+			return r.readPackedRef(name)
+		}
+
+		s := strings.TrimSpace(string(content))
+
+		target, isSymRef := strings.CutPrefix(s, "ref: ")
+		if !isSymRef {
+			// FETCH_HEAD has the object name followed by a description
+			hashStr, _, _ := strings.Cut(s, "\t")
+			return ParseHash(hashStr)
+		}
+
+		name = target
+	}
+
+	return Hash{}, fmt.Errorf("too many levels of symbolic reference: %q",
+		name)
+}
+
+// isDir returns true if the path is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+// readPackedRef finds the named reference in the packed-refs file
+func (r *Repo) readPackedRef(name string) (Hash, error) {
+	refs, err := r.packedRefs()
+	if err != nil {
+		return Hash{}, err
+	}
+
+	h, ok := refs[name]
+	if !ok {
+		return Hash{}, fmt.Errorf("reference %q: %w", name, ErrNotFound)
+	}
+
+	return h, nil
+}
+
+// packedRefs reads the packed-refs file and returns a map of the reference
+// names to the object names
+func (r *Repo) packedRefs() (map[string]Hash, error) {
+	refs := map[string]Hash{}
+
+	f, err := os.Open(filepath.Join(r.commonDir, "packed-refs"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return refs, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || line[0] == '#' || line[0] == '^' {
+			continue
+		}
+
+		hashStr, name, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+
+		h, err := ParseHash(hashStr)
+		if err != nil {
+			return nil, fmt.Errorf("bad packed-refs entry: %q: %w", line, err)
+		}
+
+		refs[name] = h
+	}
+
+	return refs, scanner.Err()
+}
+
+// isShallow returns true if the commit is one of those listed in the
+// shallow file of a shallow clone. The parents of such a commit are not in
+// the repository.
+func (r *Repo) isShallow(h Hash) (bool, error) {
+	if !r.shallowLoaded {
+		content, err := os.ReadFile(filepath.Join(r.commonDir, "shallow"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+
+		r.shallow = map[Hash]bool{}
+
+		for line := range strings.Lines(string(content)) {
+			sh, err := ParseHash(strings.TrimSpace(line))
+			if err != nil {
+				return false, fmt.Errorf("bad shallow file entry: %w", err)
+			}
+
+			r.shallow[sh] = true
+		}
+
+		r.shallowLoaded = true
+	}
+
+	return r.shallow[h], nil
+}
+
+// Refs returns a map of all the reference names starting with the given
+// prefix (such as "refs/tags/") to the object names they refer to. The
+// names are the full reference names.
+func (r *Repo) Refs(prefix string) (map[string]Hash, error) {
+	refs, err := r.packedRefs()
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range refs {
+		if !strings.HasPrefix(name, prefix) {
+			delete(refs, name)
+		}
+	}
+
+	refsDir := filepath.Join(r.commonDir, "refs")
+
+	err = filepath.WalkDir(refsDir,
+		func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			rel, err := filepath.Rel(r.commonDir, path)
+			if err != nil {
+				return err
+			}
+
+			name := filepath.ToSlash(rel)
+			if !strings.HasPrefix(name, prefix) {
+				return nil
+			}
+
+			h, err := r.readRef(name)
+			if err != nil {
+				return err
+			}
+
+			refs[name] = h
+
+			return nil
+		})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// Resolve converts a revision into the name of the commit it refers
+// to. The revision can be a full or abbreviated object name or a reference
+// name. Reference names are looked up in the same order as git uses: the
+// name as given (only if it is a full reference name or one of HEAD,
+// FETCH_HEAD, ORIG_HEAD or MERGE_HEAD), then under refs/, refs/tags/,
+// refs/heads/ and refs/remotes/. Any annotated tags are followed to the
+// tagged commit.
+func (r *Repo) Resolve(rev string) (Hash, error) {
+	h, err := r.resolveName(rev)
+	if err != nil {
+		return Hash{}, err
+	}
+
+	return r.Peel(h)
+}
+
+// resolveName converts a revision into an object name
+func (r *Repo) resolveName(rev string) (Hash, error) {
+	if h, err := ParseHash(rev); err == nil {
+		return h, nil
+	}
+
+	var names []string
+	if isPseudoRef(rev) || strings.HasPrefix(rev, "refs/") {
+		names = append(names, rev)
+	}
+
+	for _, name := range append(names,
+		"refs/"+rev,
+		"refs/tags/"+rev,
+		"refs/heads/"+rev,
+		"refs/remotes/"+rev,
+		"refs/remotes/"+rev+"/HEAD",
+	) {
+		h, err := r.readRef(name)
+		if err == nil {
+			return h, nil
+		}
+
+		if !errors.Is(err, ErrNotFound) {
+			return Hash{}, err
+		}
+	}
+
+	const minAbbrevLen = 4
+	if len(rev) >= minAbbrevLen {
+		if _, err := hex.DecodeString(rev + rev[:len(rev)%2]); err == nil {
+			return r.expandAbbrev(strings.ToLower(rev))
+		}
+	}
+
+	return Hash{}, fmt.Errorf("unknown revision %q: %w", rev, ErrNotFound)
+}
+
+// expandAbbrev finds the single object whose name starts with the
+// abbreviation
+func (r *Repo) expandAbbrev(abbrev string) (Hash, error) {
+	matches := map[Hash]bool{}
+
+	objDir := filepath.Join(r.commonDir, "objects", abbrev[:2])
+	if entries, err := os.ReadDir(objDir); err == nil {
+		for _, e := range entries {
+			name := abbrev[:2] + e.Name()
+			if strings.HasPrefix(name, abbrev) {
+				if h, err := ParseHash(name); err == nil {
+					matches[h] = true
+				}
+			}
+		}
+	}
+
+	packs, err := r.loadPacks()
+	if err != nil {
+		return Hash{}, err
+	}
+
+	for _, p := range packs {
+		for _, h := range p.idx.names {
+			if strings.HasPrefix(h.String(), abbrev) {
+				matches[h] = true
+			}
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Hash{}, fmt.Errorf("unknown revision %q: %w", abbrev, ErrNotFound)
+	case 1:
+		for h := range matches {
+			return h, nil
+		}
+	}
+
+	return Hash{}, fmt.Errorf("ambiguous object name %q", abbrev)
+}
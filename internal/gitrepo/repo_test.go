@@ -0,0 +1,246 @@
+package gitrepo_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semverparams.mod/v6/internal/gitrepo"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// runGit runs the git command in the given directory and returns its
+// output. It reports a fatal error if the command fails.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// makeTestRepo creates a git repository with a few commits and tags and
+// returns its directory
+func makeTestRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("the git program is not available")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "-b", "main")
+
+	for i, msg := range []string{
+		"feat: first",
+		"fix: second\n\nwith a body",
+		"chore: third",
+	} {
+		content := strings.Repeat("line of text\n", 100) + msg + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"),
+			[]byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		runGit(t, dir, "add", "f.txt")
+		runGit(t, dir, "commit", "-q", "-m", msg,
+			"--date", "2026-01-0"+string(rune('1'+i))+"T12:00:00Z")
+
+		if i == 0 {
+			runGit(t, dir, "tag", "v1.0.0")
+		}
+
+		if i == 1 {
+			runGit(t, dir, "tag", "-a", "-m", "annotated", "v1.1.0")
+
+			// tags with the names of files in the git directory
+			for _, name := range []string{"config", "description", "index"} {
+				runGit(t, dir, "tag", name)
+			}
+
+			// a branch with the name of a directory of tags
+			runGit(t, dir, "tag", "feature/x")
+			runGit(t, dir, "branch", "feature")
+		}
+	}
+
+	return dir
+}
+
+func testRepo(t *testing.T, dir, desc string) {
+	t.Helper()
+
+	r, err := gitrepo.Find(filepath.Join(dir, "sub", "..", "."))
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	head, err := r.Resolve("HEAD")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffString(t, desc, "HEAD",
+		head.String(), runGit(t, dir, "rev-parse", "HEAD"))
+
+	abbrev, err := r.Resolve(head.String()[:7])
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffString(t, desc, "abbreviated HEAD",
+		abbrev.String(), head.String())
+
+	v110, err := r.Resolve("v1.1.0")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffString(t, desc, "v1.1.0",
+		v110.String(), runGit(t, dir, "rev-parse", "v1.1.0^{commit}"))
+
+	commits, err := r.CommitsBetween("v1.0.0", "main")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	subjects := []string{}
+	for _, c := range commits {
+		subjects = append(subjects, c.Subject())
+	}
+
+	testhelper.DiffStringSlice(t, desc, "commit subjects",
+		subjects, []string{"chore: third", "fix: second"})
+
+	if len(commits) > 1 {
+		testhelper.DiffString(t, desc, "commit message",
+			commits[1].Message, "fix: second\n\nwith a body\n")
+	}
+
+	all, err := r.CommitsBetween("", "HEAD")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffInt(t, desc, "all commits", len(all), 3)
+
+	tags, err := r.Refs("refs/tags/")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffInt(t, desc, "tag count", len(tags), 6)
+
+	for line := range strings.SplitSeq(
+		runGit(t, dir, "rev-list", "--objects", "--all"), "\n") {
+		name, _, _ := strings.Cut(line, " ")
+
+		h, err := gitrepo.ParseHash(name)
+		if err != nil {
+			t.Fatal(desc, err)
+		}
+
+		ot, data, err := r.ReadObject(h)
+		if err != nil {
+			t.Fatal(desc, err)
+		}
+
+		testhelper.DiffString(t, desc, name+": type",
+			ot.String(), runGit(t, dir, "cat-file", "-t", name))
+		testhelper.DiffString(t, desc, name+": size",
+			strconv.Itoa(len(data)), runGit(t, dir, "cat-file", "-s", name))
+	}
+
+	for _, name := range []string{"config", "description", "index"} {
+		h, err := r.Resolve(name)
+		if err != nil {
+			t.Fatal(desc, err)
+		}
+
+		testhelper.DiffString(t, desc, "tag: "+name,
+			h.String(), v110.String())
+	}
+
+	feature, err := r.Resolve("feature")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffString(t, desc, "branch: feature",
+		feature.String(), v110.String())
+
+	fullName, err := r.Resolve("refs/tags/v1.1.0")
+	if err != nil {
+		t.Fatal(desc, err)
+	}
+
+	testhelper.DiffString(t, desc, "refs/tags/v1.1.0",
+		fullName.String(), v110.String())
+
+	_, err = r.Resolve("nonesuch")
+	testhelper.CheckExpErrWithID(t, desc, err,
+		testhelper.MkExpErr(`unknown revision "nonesuch"`))
+}
+
+func TestRepo(t *testing.T) {
+	dir := makeTestRepo(t)
+
+	testRepo(t, dir, "loose objects")
+
+	runGit(t, dir, "gc", "-q", "--aggressive")
+	runGit(t, dir, "pack-refs", "--all")
+
+	testRepo(t, dir, "packed objects")
+
+	shallowDir := t.TempDir()
+	runGit(t, shallowDir, "clone", "-q", "--depth", "2",
+		"file://"+dir, "clone")
+
+	r, err := gitrepo.Open(filepath.Join(shallowDir, "clone"))
+	if err != nil {
+		t.Fatal("shallow clone:", err)
+	}
+
+	commits, err := r.CommitsBetween("", "HEAD")
+	if err != nil {
+		t.Fatal("shallow clone:", err)
+	}
+
+	testhelper.DiffInt(t, "shallow clone", "commits", len(commits), 2)
+
+	badTag := filepath.Join(t.TempDir(), "bad-tag")
+	if err := os.WriteFile(badTag,
+		[]byte("object nonesuch\ntype commit\ntag bad\n\nbad tag\n"),
+		0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	badTagName := runGit(t, dir,
+		"hash-object", "-t", "tag", "-w", "--literally", badTag)
+
+	r, err = gitrepo.Open(dir)
+	if err != nil {
+		t.Fatal("bad tag:", err)
+	}
+
+	_, err = r.Resolve(badTagName)
+	testhelper.CheckExpErrWithID(t, "bad tag", err,
+		testhelper.MkExpErr("tag object "+badTagName+": "))
+
+	_, err = gitrepo.Open(t.TempDir())
+	testhelper.CheckExpErrWithID(t, "not a repo", err,
+		testhelper.MkExpErr("is not a git repository"))
+}
@@ -3,9 +3,13 @@ package semverparams
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 
 	"github.com/nickwells/check.mod/v2/check"
 	"github.com/nickwells/checksetter.mod/v4/checksetter"
+	"github.com/nickwells/param.mod/v7/paction"
 	"github.com/nickwells/param.mod/v7/param"
 	"github.com/nickwells/param.mod/v7/psetter"
 	"github.com/nickwells/semver.mod/v3/semver"
@@ -16,6 +20,11 @@ import (
 // have an empty Prefix but each of the rest will need to have its own
 // distinct Prefix. When you add the parameters for these they will all
 // appear in the same parameter group.
+//
+// The parameters of a SemverVals may be added to more than one PSet (for
+// instance, to the PSets of several sub-commands) but they may only be
+// added once to each PSet. Where the values were set is recorded for each
+// PSet, see SemVerHasBeenSetIn and the related methods.
 type SemverVals struct {
 	// Prefix is the optional prefix to apply to the parameter names. If it
 	// is not empty it will be separated from the rest of the parameter name
@@ -31,62 +40,246 @@ type SemverVals struct {
 	// be separated from the rest of the error message with ': '
 	Desc string
 
-	// SemVer is a semantic version number that will be set by the parameter
-	// parsing if it is passed to the program
-	SemVer      semver.SV
-	semverParam *param.ByName
+	// GroupName, if not empty, is the name of the parameter group in which
+	// the parameters will appear, it defaults to "semver". GroupDesc is the
+	// description of the group, if it is empty the description of the
+	// standard semver group is used. The group is added to the PSet, if
+	// necessary, when the parameters are added. For instance, a tool might
+	// put the parameters for its own versions in a "release" group.
+	GroupName string
+	GroupDesc string
+
+	// TagPrefix is the optional directory part of the tag names used for
+	// this version number, as used for the sub-modules of a monorepo (for
+	// instance, "services/billing" for tags such as
+	// "services/billing/v1.4.0"). Only tags with this prefix are seen when
+	// reading tags and the semver parameter will also accept the full tag
+	// name.
+	TagPrefix string
 
-	// SemverAttrs gives the attributes to be applied to the parameter for
-	// setting the SemVer
+	// SemVer is a semantic version number that will be set by the parameter
+	// parsing if it is passed to the program, either directly or from a
+	// project manifest file
+	SemVer semver.SV
+
+	// SemverAttrs gives the attributes to be applied to the parameters for
+	// setting the SemVer. The SemVer can be set by either of the
+	// parameters so, rather than being applied to each parameter,
+	// param.MustBeSet is checked after parsing: one of them must be given.
 	SemverAttrs param.Attributes
 
+	// SemverParam and SemverFromParam allow the names and help text of the
+	// parameters for setting the SemVer (by default "semver", with the
+	// alternative name "svn", and "semver-from") to be changed
+	SemverParam     ParamSpec
+	SemverFromParam ParamSpec
+
 	// PreRelIDs is a list of Pre-Release IDs that will be set by the parameter
 	// parsing if the list is passed to the program
-	PreRelIDs      []string
-	preRelIDsParam *param.ByName
+	PreRelIDs []string
 
 	// PreRelIDAttrs gives the attributes to be applied to the parameter for
 	// setting the pre-release IDs
 	PreRelIDAttrs param.Attributes
 
+	// PreRelIDsParam allows the names and help text of the parameter for
+	// setting the pre-release IDs (by default "pre-rel-IDs", with the
+	// alternative name "prIDs") to be changed
+	PreRelIDsParam ParamSpec
+
 	// BuildIDs is a list of Build IDs that will be set by the parameter parsing
 	// if the list is passed to the program
-	BuildIDs      []string
-	buildIDsParam *param.ByName
+	BuildIDs []string
 
 	// BuildIDAttrs gives the attributes to be applied to the parameter for
 	// setting the build IDs
 	BuildIDAttrs param.Attributes
+
+	// BuildIDsParam allows the names and help text of the parameter for
+	// setting the build IDs (by default "build-IDs", with the alternative
+	// name "bldIDs") to be changed
+	BuildIDsParam ParamSpec
+
+	// BuildIDSource supplies the values used when the build IDs are
+	// generated (when the build-IDs parameter is given as "auto" or a
+	// template, see GenerateBuildIDs). The zero value uses the standard
+	// values.
+	BuildIDSource BuildIDSource
+
+	// Format is the format to be used when printing the SemVer, it can be
+	// set by the semver-format parameter (see AddFormatParam)
+	Format SVFormat
+
+	// OutputFormat and OutputFile give the format and destination of the
+	// output written by the WriteOutput method, they can be set by the
+	// semver-output parameters (see AddOutputParams)
+	OutputFormat OutputFormat
+	OutputFile   string
+
+	// BumpReasons records the bump inferred from each commit message when
+	// the SemVer is bumped automatically (see AddBumpParams)
+	BumpReasons []CommitBump
+
+	// BumpParam, BumpCommitsParam and BumpGitRevsParam allow the names and
+	// help text of the parameters for bumping the SemVer (by default
+	// "bump", "bump-commits" and "bump-git-revs") to be changed
+	BumpParam        ParamSpec
+	BumpCommitsParam ParamSpec
+	BumpGitRevsParam ParamSpec
+
+	// Channel is the name of the release channel in which the SemVer
+	// should be, it can be set by the channel parameter (see
+	// AddChannelParam)
+	Channel string
+
+	// ChannelParam allows the name and help text of the parameter for
+	// setting the Channel (by default "channel") to be changed
+	ChannelParam ParamSpec
+
+	// StdW is the writer to which any reports are written. If it is nil
+	// os.Stdout is used.
+	StdW io.Writer
+
+	// ErrW is the writer to which any warnings are written, such as when a
+	// deprecated parameter name is used (see DeprecatedAlias). If it is nil
+	// os.Stderr is used.
+	ErrW io.Writer
+
+	// params records the parameters added to each PSet, see paramsFor
+	params []*psetParams
+}
+
+// psetParams records the parameters for setting the values of a SemverVals
+// which have been added to a PSet. A SemverVals can have its parameters
+// added to several PSets (for instance, one per sub-command) and this
+// allows us to report where the values have been set for each one.
+type psetParams struct {
+	ps *param.PSet
+
+	semver     *param.ByName
+	semverFrom *param.ByName
+	preRelIDs  *param.ByName
+	buildIDs   *param.ByName
+	bump       *param.ByName
+	channel    *param.ByName
+
+	// sources records where each of the values was set, see SemVerSource
+	// and related methods
+	sources map[string][]ValSource
+
+	// bumpedFrom records the SemVer before it was bumped by the bump
+	// parameters, it is nil if it has not been bumped
+	bumpedFrom *semver.SV
+}
+
+// stdW returns the writer for any reports
+func (svv SemverVals) stdW() io.Writer {
+	if svv.StdW == nil {
+		return os.Stdout
+	}
+
+	return svv.StdW
+}
+
+// errW returns the writer for any warnings
+func (svv SemverVals) errW() io.Writer {
+	if svv.ErrW == nil {
+		return os.Stderr
+	}
+
+	return svv.ErrW
+}
+
+// paramsFor returns the record of the parameters added to the PSet,
+// creating it if necessary
+func (svv *SemverVals) paramsFor(ps *param.PSet) *psetParams {
+	for _, pp := range svv.params {
+		if pp.ps == ps {
+			return pp
+		}
+	}
+
+	pp := &psetParams{ps: ps}
+	svv.params = append(svv.params, pp)
+
+	return pp
+}
+
+// findParams returns the record of the parameters added to the PSet or nil
+// if no parameters have been added to it
+func (svv SemverVals) findParams(ps *param.PSet) *psetParams {
+	for _, pp := range svv.params {
+		if pp.ps == ps {
+			return pp
+		}
+	}
+
+	return nil
+}
+
+// isSet returns true if the parameter has been added and has been set
+func isSet(p *param.ByName) bool {
+	return p != nil && p.HasBeenSet()
+}
+
+// semVerHasBeenSet returns true if the SemVer parameters have been added
+// to the PSet and one of them has been set
+func (pp *psetParams) semVerHasBeenSet() bool {
+	return pp != nil && (isSet(pp.semver) || isSet(pp.semverFrom))
+}
+
+// preRelIDsHaveBeenSet returns true if the PreRelIDs parameter has been
+// added to the PSet and it has been set
+func (pp *psetParams) preRelIDsHaveBeenSet() bool {
+	return pp != nil && isSet(pp.preRelIDs)
+}
+
+// buildIDsHaveBeenSet returns true if the BuildIDs parameter has been added
+// to the PSet and it has been set
+func (pp *psetParams) buildIDsHaveBeenSet() bool {
+	return pp != nil && isSet(pp.buildIDs)
 }
 
 // SemVerHasBeenSet returns true if the SemVer value has been set after
-// parameter parsing
+// parameter parsing, either directly or from a project manifest file. If the
+// parameters have been added to more than one PSet it returns true if the
+// value has been set by any of them, see SemVerHasBeenSetIn.
 func (svv SemverVals) SemVerHasBeenSet() bool {
-	if svv.semverParam == nil {
-		return false
-	}
+	return slices.ContainsFunc(svv.params, (*psetParams).semVerHasBeenSet)
+}
 
-	return svv.semverParam.HasBeenSet()
+// SemVerHasBeenSetIn returns true if the SemVer value has been set by the
+// parameters added to the given PSet
+func (svv SemverVals) SemVerHasBeenSetIn(ps *param.PSet) bool {
+	return svv.findParams(ps).semVerHasBeenSet()
 }
 
 // PreRelIDsHaveBeenSet returns true if the PreRelIDs value has been set after
-// parameter parsing
+// parameter parsing. If the parameters have been added to more than one PSet
+// it returns true if the value has been set by any of them, see
+// PreRelIDsHaveBeenSetIn.
 func (svv SemverVals) PreRelIDsHaveBeenSet() bool {
-	if svv.preRelIDsParam == nil {
-		return false
-	}
+	return slices.ContainsFunc(svv.params, (*psetParams).preRelIDsHaveBeenSet)
+}
 
-	return svv.preRelIDsParam.HasBeenSet()
+// PreRelIDsHaveBeenSetIn returns true if the PreRelIDs value has been set by
+// the parameters added to the given PSet
+func (svv SemverVals) PreRelIDsHaveBeenSetIn(ps *param.PSet) bool {
+	return svv.findParams(ps).preRelIDsHaveBeenSet()
 }
 
 // BuildIDsHaveBeenSet returns true if the BuildIDs value has been set after
-// parameter parsing
+// parameter parsing. If the parameters have been added to more than one PSet
+// it returns true if the value has been set by any of them, see
+// BuildIDsHaveBeenSetIn.
 func (svv SemverVals) BuildIDsHaveBeenSet() bool {
-	if svv.buildIDsParam == nil {
-		return false
-	}
+	return slices.ContainsFunc(svv.params, (*psetParams).buildIDsHaveBeenSet)
+}
 
-	return svv.buildIDsParam.HasBeenSet()
+// BuildIDsHaveBeenSetIn returns true if the BuildIDs value has been set by
+// the parameters added to the given PSet
+func (svv SemverVals) BuildIDsHaveBeenSetIn(ps *param.PSet) bool {
+	return svv.findParams(ps).buildIDsHaveBeenSet()
 }
 
 // SemverChecks holds the checks to be applied to the pre-release and build
@@ -114,10 +307,39 @@ type SemverChecks struct {
 	Desc string
 
 	// PreRelIDChecks is a list of checks to be applied to the pre-release IDs
-	PreRelIDChecks []check.ValCk[[]string]
+	PreRelIDChecks     []check.ValCk[[]string]
+	preRelIDChecksText string
 
 	// BuildIDChecks is a list of checks to be applied to the build IDs
-	BuildIDChecks []check.ValCk[[]string]
+	BuildIDChecks     []check.ValCk[[]string]
+	buildIDChecksText string
+
+	// PreRelIDChecksParam and BuildIDChecksParam allow the names and help
+	// text of the parameters for setting the checks (by default
+	// "pre-rel-ID-checks" and "build-ID-checks", with the alternative names
+	// "prID-checks" and "bldID-checks") to be changed
+	PreRelIDChecksParam ParamSpec
+	BuildIDChecksParam  ParamSpec
+
+	// Channels gives the release channels and the moves allowed between
+	// them, see SemverVals.AddChannelParam. If it has no channels the
+	// DefaultChannelTable is used. It can be set by the parameters added
+	// by AddChannelTableParams.
+	Channels ChannelTable
+
+	// ErrW is the writer to which any warnings are written, such as when a
+	// deprecated parameter name is used (see DeprecatedAlias). If it is nil
+	// os.Stderr is used.
+	ErrW io.Writer
+}
+
+// errW returns the writer for any warnings
+func (svCks SemverChecks) errW() io.Writer {
+	if svCks.ErrW == nil {
+		return os.Stderr
+	}
+
+	return svCks.ErrW
 }
 
 const (
@@ -125,17 +347,52 @@ const (
 	semverChecksGroupName = "semver-checks"
 )
 
+// semverGroupDesc is the description of the semver group
+const semverGroupDesc = "common parameters concerned with " + semver.Names
+
 // AddSemverGroup adds the group for the common semantic versioning-related
-// parameters
+// parameters. It may be called more than once. Note that the functions
+// which add the parameters will add the group themselves so there is no
+// need to call this, it is kept for compatibility.
 func AddSemverGroup(ps *param.PSet) error {
-	ps.AddGroup(semverGroupName,
-		"common parameters concerned with "+semver.Names)
+	ps.AddGroup(semverGroupName, semverGroupDesc)
 
 	return nil
 }
 
-// AddSemverParam returns a function that will add a parameter for setting
-// the semantic version number to the passed PSet
+// addGroup adds the parameter group for the SemverVals to the PSet, unless
+// it has been added already, and returns its name. It returns an error if
+// the group has already been added with a different description.
+func (svv SemverVals) addGroup(ps *param.PSet) (string, error) {
+	name := svv.GroupName
+	if name == "" {
+		name = semverGroupName
+	}
+
+	g, exists := ps.GetGroup(name)
+
+	switch {
+	case !exists:
+		desc := svv.GroupDesc
+		if desc == "" {
+			desc = semverGroupDesc
+		}
+
+		ps.AddGroup(name, desc)
+	case svv.GroupDesc == "" || svv.GroupDesc == g.Desc():
+	case g.Desc() == "":
+		ps.AddGroup(name, svv.GroupDesc)
+	default:
+		return "", fmt.Errorf("the %q parameter group has already been added"+
+			" with a different description: %q", name, g.Desc())
+	}
+
+	return name, nil
+}
+
+// AddSemverParam returns a function that will add parameters for setting
+// the semantic version number to the passed PSet. The number can be given
+// directly or read from a project manifest file but not both.
 func (svv *SemverVals) AddSemverParam(svCks *SemverChecks) param.PSetOptFunc {
 	return func(ps *param.PSet) error {
 		prefix := ""
@@ -143,13 +400,56 @@ func (svv *SemverVals) AddSemverParam(svCks *SemverChecks) param.PSetOptFunc {
 			prefix = svv.Prefix + "-"
 		}
 
-		svv.semverParam = ps.Add(prefix+"semver", SVSetter{Value: &svv.SemVer},
-			"specify the "+semver.Name+" to be used",
-			param.AltNames(prefix+"svn"),
-			param.GroupName(semverGroupName),
-			param.Attrs(svv.SemverAttrs),
+		var (
+			semverPN = svv.SemverParam.resolve(prefix,
+				"semver", []string{"svn"},
+				"specify the "+semver.Name+" to be used")
+			semverFromPN = svv.SemverFromParam.resolve(prefix,
+				"semver-from", nil,
+				"specify a project manifest file (such as package.json)"+
+					" from which to read the "+semver.Name+" to be used")
+		)
+
+		pp := svv.paramsFor(ps)
+		if pp.semver != nil {
+			return fmt.Errorf("the %q parameter has already been added"+
+				" to this PSet", semverPN.name)
+		}
+
+		if err := checkParamNames(ps, semverPN, semverFromPN); err != nil {
+			return err
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		pp.semver = ps.Add(semverPN.name,
+			SVSetter{Value: &svv.SemVer, TagPrefix: svv.TagPrefix},
+			semverPN.help,
+			param.AltNames(semverPN.altNames...),
+			param.PostAction(semverPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.Attrs(svv.SemverAttrs&^param.MustBeSet),
+			param.SeeAlso(semverFromPN.name),
+			param.PostAction(pp.recordSource(srcValSemVer)),
+		)
+
+		pp.semverFrom = ps.Add(semverFromPN.name,
+			ManifestSetter{Value: &svv.SemVer},
+			semverFromPN.help,
+			param.AltNames(semverFromPN.altNames...),
+			param.PostAction(semverFromPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.Attrs(svv.SemverAttrs&^param.MustBeSet),
+			param.SeeAlso(semverPN.name),
+			param.PostAction(pp.recordSource(srcValSemVer)),
 		)
 
+		ps.AddFinalCheck(
+			checkSemverSource(svv, pp, semverPN.name, semverFromPN.name))
+
 		if svCks != nil {
 			ps.AddFinalCheck(
 				checkSemverIDs(svv, svCks))
@@ -159,6 +459,33 @@ func (svv *SemverVals) AddSemverParam(svCks *SemverChecks) param.PSetOptFunc {
 	}
 }
 
+// checkSemverSource checks that the SemVer has not been set by both of the
+// parameters and, if the SemverAttrs include param.MustBeSet, that it has
+// been set by one of them
+func checkSemverSource(
+	svv *SemverVals, pp *psetParams, semverName, semverFromName string,
+) param.FinalCheckFunc {
+	return func() error {
+		errPfx := ""
+
+		if svv.Desc != "" {
+			errPfx = svv.Desc + ": "
+		}
+
+		if isSet(pp.semver) && isSet(pp.semverFrom) {
+			return fmt.Errorf("%sonly one of the %q and %q parameters"+
+				" may be given", errPfx, semverName, semverFromName)
+		}
+
+		if svv.SemverAttrs&param.MustBeSet != 0 && !pp.semVerHasBeenSet() {
+			return fmt.Errorf("%sone of the %q or %q parameters must be given",
+				errPfx, semverName, semverFromName)
+		}
+
+		return nil
+	}
+}
+
 // IDListSetter will return a psetter.StrList[string] correctly constructed for
 // setting a list of semver IDs (either pre-release or build IDs). You should
 // pass the appropriate semver.Check...ID function depending on the type of
@@ -196,31 +523,61 @@ func (svv *SemverVals) AddIDParams(svCks *SemverChecks) param.PSetOptFunc {
 		}
 
 		var (
-			preRelIDsParamName = prefix + "pre-rel-IDs"
-			buildIDsParamName  = prefix + "build-IDs"
-
-			preRelIDsAltNames = []string{prefix + "prIDs"}
-			buildIDsAltNames  = []string{prefix + "bldIDs"}
+			preRelIDsPN = svv.PreRelIDsParam.resolve(prefix,
+				"pre-rel-IDs", []string{"prIDs"},
+				"specify a non-empty list of pre-release IDs"+
+					" suitable for setting on a "+semver.Name)
+			buildIDsPN = svv.BuildIDsParam.resolve(prefix,
+				"build-IDs", []string{"bldIDs"},
+				"specify a non-empty list of build IDs"+
+					" suitable for setting on a "+semver.Name+
+					", or '"+BuildIDsAuto+"' or a template"+
+					" from which to generate them")
 		)
 
-		svv.preRelIDsParam = ps.Add(preRelIDsParamName,
-			IDListSetter(&svv.PreRelIDs, semver.CheckPreRelID),
-			"specify a non-empty list of pre-release IDs"+
-				" suitable for setting on a "+semver.Name,
-			param.AltNames(preRelIDsAltNames...),
-			param.GroupName(semverGroupName),
+		pp := svv.paramsFor(ps)
+		if pp.preRelIDs != nil {
+			return fmt.Errorf("the %q parameter has already been added"+
+				" to this PSet", preRelIDsPN.name)
+		}
+
+		if err := checkParamNames(ps, preRelIDsPN, buildIDsPN); err != nil {
+			return err
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		pp.preRelIDs = ps.Add(preRelIDsPN.name,
+			IDListCompleter{
+				StrList: IDListSetter(&svv.PreRelIDs, semver.CheckPreRelID),
+				Candidates: func() []string {
+					return []string{NextPreRelIDs(latestTagSV(svv.TagPrefix))}
+				},
+			},
+			preRelIDsPN.help,
+			param.AltNames(preRelIDsPN.altNames...),
+			param.PostAction(preRelIDsPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
 			param.Attrs(svv.PreRelIDAttrs),
-			param.SeeAlso(buildIDsParamName),
+			param.SeeAlso(buildIDsPN.name),
+			param.PostAction(pp.recordSource(srcValPreRelIDs)),
 		)
 
-		svv.buildIDsParam = ps.Add(buildIDsParamName,
-			IDListSetter(&svv.BuildIDs, semver.CheckBuildID),
-			"specify a non-empty list of build IDs"+
-				" suitable for setting on a "+semver.Name,
-			param.AltNames(buildIDsAltNames...),
-			param.GroupName(semverGroupName),
+		pp.buildIDs = ps.Add(buildIDsPN.name,
+			BuildIDsSetter{
+				StrList: IDListSetter(&svv.BuildIDs, semver.CheckBuildID),
+				Source:  &svv.BuildIDSource,
+			},
+			buildIDsPN.help,
+			param.AltNames(buildIDsPN.altNames...),
+			param.PostAction(buildIDsPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
 			param.Attrs(svv.BuildIDAttrs),
-			param.SeeAlso(preRelIDsParamName),
+			param.SeeAlso(preRelIDsPN.name),
+			param.PostAction(pp.recordSource(srcValBuildIDs)),
 		)
 
 		if svCks != nil {
@@ -241,18 +598,8 @@ func checkIDs(svv *SemverVals, svCks *SemverChecks) param.FinalCheckFunc {
 			errPfx = svv.Desc + ": "
 		}
 
-		for _, chk := range svCks.PreRelIDChecks {
-			err := chk(svv.PreRelIDs)
-			if err != nil {
-				return fmt.Errorf("%sBad PreRelIDs: %s", errPfx, err)
-			}
-		}
-
-		for _, chk := range svCks.BuildIDChecks {
-			err := chk(svv.BuildIDs)
-			if err != nil {
-				return fmt.Errorf("%sBad BuildIDs: %s", errPfx, err)
-			}
+		if err := svCks.checkIDLists(svv.PreRelIDs, svv.BuildIDs); err != nil {
+			return fmt.Errorf("%s%w", errPfx, err)
 		}
 
 		return nil
@@ -269,44 +616,72 @@ func checkSemverIDs(svv *SemverVals, svCks *SemverChecks) param.FinalCheckFunc {
 			errPfx = svv.Desc + ": "
 		}
 
-		for _, chk := range svCks.PreRelIDChecks {
-			err := chk(svv.SemVer.PreRelIDs())
-			if err != nil {
-				return fmt.Errorf("%sBad PreRelIDs: %s", errPfx, err)
-			}
-		}
-
-		for _, chk := range svCks.BuildIDChecks {
-			err := chk(svv.SemVer.BuildIDs())
-			if err != nil {
-				return fmt.Errorf("%sBad BuildIDs: %s", errPfx, err)
-			}
+		if err := svCks.Check(&svv.SemVer); err != nil {
+			return fmt.Errorf("%s%w", errPfx, err)
 		}
 
 		return nil
 	}
 }
 
-// AddCheckParams will add parameters for setting the checks to be
-// applied to any pre-release and build IDs of a semantic version number.
-func (svCks *SemverChecks) AddCheckParams() param.PSetOptFunc {
-	return func(ps *param.PSet) error {
-		prefix := ""
-		groupName := semverChecksGroupName
+// Check returns a non-nil error if the pre-release or build IDs of the
+// semantic version number fail any of the checks
+func (svCks SemverChecks) Check(sv *semver.SV) error {
+	return svCks.checkIDLists(sv.PreRelIDs(), sv.BuildIDs())
+}
 
-		if svCks.Name != "" {
-			prefix = svCks.Name + "-"
-			groupName += "-" + svCks.Name
+// checkIDLists returns a non-nil error if the pre-release or build IDs fail
+// any of the checks
+func (svCks SemverChecks) checkIDLists(preRelIDs, buildIDs []string) error {
+	for _, chk := range svCks.PreRelIDChecks {
+		err := chk(preRelIDs)
+		if err != nil {
+			return fmt.Errorf("Bad PreRelIDs: %w", err)
 		}
+	}
 
+	for _, chk := range svCks.BuildIDChecks {
+		err := chk(buildIDs)
+		if err != nil {
+			return fmt.Errorf("Bad BuildIDs: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// addGroup adds the parameter group for the SemverChecks to the PSet,
+// unless it has been added already, and returns its name. The config files
+// for the standard group are added if the group has none.
+func (svCks SemverChecks) addGroup(ps *param.PSet) string {
+	groupName := semverChecksGroupName
+	if svCks.Name != "" {
+		groupName += "-" + svCks.Name
+	}
+
+	g, exists := ps.GetGroup(groupName)
+	if !exists {
 		ps.AddGroup(groupName,
 			"common parameters for specifying checks on "+
 				semver.Name+
 				" (pre-release and build IDs)"+svCks.Desc)
+	}
 
-		if svCks.Name == "" {
-			_ = setGlobalConfigFileForGroupSemverChecks(ps)
-			_ = setConfigFileForGroupSemverChecks(ps)
+	if svCks.Name == "" && (!exists || len(g.ConfigFiles()) == 0) {
+		_ = setGlobalConfigFileForGroupSemverChecks(ps)
+		_ = setConfigFileForGroupSemverChecks(ps)
+	}
+
+	return groupName
+}
+
+// AddCheckParams will add parameters for setting the checks to be
+// applied to any pre-release and build IDs of a semantic version number.
+func (svCks *SemverChecks) AddCheckParams() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svCks.Name != "" {
+			prefix = svCks.Name + "-"
 		}
 
 		helpText := func(part string) string {
@@ -316,26 +691,48 @@ func (svCks *SemverChecks) AddCheckParams() param.PSetOptFunc {
 				part, semver.Name, svCks.Desc)
 		}
 
-		ps.Add(prefix+"pre-rel-ID-checks",
+		var (
+			preRelIDChecksPN = svCks.PreRelIDChecksParam.resolve(prefix,
+				"pre-rel-ID-checks", []string{"prID-checks"},
+				helpText("pre-release IDs"))
+			buildIDChecksPN = svCks.BuildIDChecksParam.resolve(prefix,
+				"build-ID-checks", []string{"bldID-checks"},
+				helpText("build IDs"))
+		)
+
+		err := checkParamNames(ps, preRelIDChecksPN, buildIDChecksPN)
+		if err != nil {
+			return err
+		}
+
+		groupName := svCks.addGroup(ps)
+
+		ps.Add(preRelIDChecksPN.name,
 			&checksetter.Setter[[]string]{
 				Value: &svCks.PreRelIDChecks,
 				Parser: checksetter.FindParserOrPanic[[]string](
 					checksetter.StringSliceCheckerName),
 			},
-			helpText("pre-release IDs"),
-			param.AltNames(prefix+"prID-checks"),
+			preRelIDChecksPN.help,
+			param.AltNames(preRelIDChecksPN.altNames...),
+			param.PostAction(preRelIDChecksPN.deprecationAction(svCks.errW())),
 			param.GroupName(groupName),
+			param.PostAction(
+				paction.CaptureParamVal(&svCks.preRelIDChecksText)),
 		)
 
-		ps.Add(prefix+"build-ID-checks",
+		ps.Add(buildIDChecksPN.name,
 			&checksetter.Setter[[]string]{
 				Value: &svCks.BuildIDChecks,
 				Parser: checksetter.FindParserOrPanic[[]string](
 					checksetter.StringSliceCheckerName),
 			},
-			helpText("build IDs"),
-			param.AltNames(prefix+"bldID-checks"),
+			buildIDChecksPN.help,
+			param.AltNames(buildIDChecksPN.altNames...),
+			param.PostAction(buildIDChecksPN.deprecationAction(svCks.errW())),
 			param.GroupName(groupName),
+			param.PostAction(
+				paction.CaptureParamVal(&svCks.buildIDChecksText)),
 		)
 
 		return nil
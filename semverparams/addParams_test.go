@@ -11,75 +11,10 @@ import (
 	"github.com/nickwells/param.mod/v7/paramtest"
 	"github.com/nickwells/semver.mod/v3/semver"
 	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/semverparams.mod/v6/semverparamstest"
 	"github.com/nickwells/testhelper.mod/v2/testhelper"
 )
 
-type semverPair struct {
-	svv   *semverparams.SemverVals
-	svCks *semverparams.SemverChecks
-
-	addIDs bool
-}
-
-func makePSet(svp *semverPair) *param.PSet {
-	ps := paramset.NewNoHelpNoExitNoErrRpt()
-
-	if svp.svv != nil {
-		_ = semverparams.AddSemverGroup(ps)
-		_ = svp.svv.AddSemverParam(svp.svCks)(ps)
-
-		if svp.addIDs {
-			_ = svp.svv.AddIDParams(svp.svCks)(ps)
-		}
-	}
-
-	if svp.svCks != nil {
-		_ = svp.svCks.AddCheckParams()(ps)
-	}
-
-	return ps
-}
-
-// cmpSemverPairs compares the value with the expected value and returns
-// an error if they differ
-func cmpSemverPairs(iVal, iExpVal any) error {
-	val, ok := iVal.(semverPair)
-	if !ok {
-		return errors.New("Bad value: not a semverPair struct")
-	}
-
-	expVal, ok := iExpVal.(semverPair)
-	if !ok {
-		return errors.New("Bad expected value: not a semverPair struct")
-	}
-
-	return testhelper.DiffVals(val, expVal,
-		[]string{"svCks"},
-		[]string{"svv", "semverParam"},
-		[]string{"svv", "preRelIDsParam"},
-		[]string{"svv", "buildIDsParam"})
-}
-
-// mkTestParser populates and returns a paramtest.Parser ready to be added to
-// the testcases.
-func mkTestParser(
-	errs errutil.ErrMap,
-	id testhelper.ID,
-	initVals semverPair,
-	expVals semverPair,
-	args ...string,
-) paramtest.Parser {
-	return paramtest.Parser{
-		ID:             id,
-		ExpParseErrors: errs,
-		Val:            initVals,
-		Ps:             makePSet(&initVals),
-		ExpVal:         expVals,
-		Args:           args,
-		CheckFunc:      cmpSemverPairs,
-	}
-}
-
 func TestParse(t *testing.T) {
 	testCases := []paramtest.Parser{}
 
@@ -90,10 +25,11 @@ func TestParse(t *testing.T) {
 		}
 
 		testCases = append(testCases,
-			mkTestParser(errutil.ErrMap{},
+			semverparamstest.NewParser(t,
 				testhelper.MkID("good semver, no prefix"),
-				semverPair{svv: &svvInit},
-				semverPair{svv: &svvExp},
+				nil,
+				semverparamstest.Pair{Vals: &svvInit},
+				semverparamstest.Pair{Vals: &svvExp},
 				"-semver", "v1.2.3"))
 	}
 	{
@@ -106,12 +42,27 @@ func TestParse(t *testing.T) {
 		}
 
 		testCases = append(testCases,
-			mkTestParser(errutil.ErrMap{},
+			semverparamstest.NewParser(t,
 				testhelper.MkID("good semver, prefix: a"),
-				semverPair{svv: &svvInit},
-				semverPair{svv: &svvExp},
+				nil,
+				semverparamstest.Pair{Vals: &svvInit},
+				semverparamstest.Pair{Vals: &svvExp},
 				"-a-semver", "v1.2.3"))
 	}
+	{
+		svvInit := semverparams.SemverVals{}
+		svvExp := semverparams.SemverVals{
+			SemVer: *semver.NewSVOrPanic(1, 4, 0, []string{"rc", "2"}, nil),
+		}
+
+		testCases = append(testCases,
+			semverparamstest.NewParser(t,
+				testhelper.MkID("good semver from manifest"),
+				nil,
+				semverparamstest.Pair{Vals: &svvInit},
+				semverparamstest.Pair{Vals: &svvExp},
+				"-semver-from", "testdata/manifest/npm/package.json"))
+	}
 	{
 		svvInit := semverparams.SemverVals{}
 		svCksInit := semverparams.SemverChecks{}
@@ -122,10 +73,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(errutil.ErrMap{},
+			semverparamstest.NewParser(t,
 				testhelper.MkID("good semver, with prID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				nil,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-semver", "v1.2.3-rc.1",
 				"-pre-rel-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -147,10 +103,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(errutil.ErrMap{},
+			semverparamstest.NewParser(t,
 				testhelper.MkID("good semver, with buildID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				nil,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-semver", "v1.2.3+rc.1",
 				"-build-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -183,10 +144,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(parseErrs,
+			semverparamstest.NewParser(t,
 				testhelper.MkID("bad semver, with prID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				parseErrs,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-semver", "v1.2.3-rc.1.x",
 				"-pre-rel-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -219,10 +185,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(parseErrs,
+			semverparamstest.NewParser(t,
 				testhelper.MkID("bad semver, with buildID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				parseErrs,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-semver", "v1.2.3+rc.1.x",
 				"-build-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -260,10 +231,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(parseErrs,
+			semverparamstest.NewParser(t,
 				testhelper.MkID("bad pre-release IDs, with prID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				parseErrs,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-test-pfx-pre-rel-IDs", "rc.1.x",
 				"-test-name-pre-rel-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -299,10 +275,15 @@ func TestParse(t *testing.T) {
 		svCksExp := semverparams.SemverChecks{}
 
 		testCases = append(testCases,
-			mkTestParser(parseErrs,
+			semverparamstest.NewParser(t,
 				testhelper.MkID("bad build IDs, with buildID checks"),
-				semverPair{svv: &svvInit, svCks: &svCksInit, addIDs: true},
-				semverPair{svv: &svvExp, svCks: &svCksExp, addIDs: true},
+				parseErrs,
+				semverparamstest.Pair{
+					Vals: &svvInit, Checks: &svCksInit, AddIDParams: true,
+				},
+				semverparamstest.Pair{
+					Vals: &svvExp, Checks: &svCksExp, AddIDParams: true,
+				},
 				"-build-IDs", "rc.1.x",
 				"-test-name-build-ID-checks",
 				`Or(Length(EQ(0)),`+
@@ -414,3 +395,366 @@ func TestIDListSetter(t *testing.T) {
 		}
 	}
 }
+
+func TestMultiplePSets(t *testing.T) {
+	svv := semverparams.SemverVals{}
+
+	ps1 := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+	)
+	ps2 := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+		svv.AddIDParams(nil),
+	)
+	ps3 := paramset.NewNoHelpNoExitNoErrRpt()
+
+	ps1.Parse([]string{})
+	ps2.Parse([]string{"-semver", "v1.2.3", "-build-IDs", "b1"})
+
+	for _, ps := range []*param.PSet{ps1, ps2} {
+		if errMap := ps.Errors(); len(errMap) != 0 {
+			t.Fatal("unexpected errors:", errMap)
+		}
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		ps                                    *param.PSet
+		expSemVerSet, expPreRelSet, expBldSet bool
+	}{
+		{
+			ID: testhelper.MkID("first PSet, nothing set"),
+			ps: ps1,
+		},
+		{
+			ID:           testhelper.MkID("second PSet, semver and build IDs set"),
+			ps:           ps2,
+			expSemVerSet: true,
+			expBldSet:    true,
+		},
+		{
+			ID: testhelper.MkID("PSet without the parameters"),
+			ps: ps3,
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffBool(t, tc.IDStr(), "SemVerHasBeenSetIn",
+			svv.SemVerHasBeenSetIn(tc.ps), tc.expSemVerSet)
+		testhelper.DiffBool(t, tc.IDStr(), "PreRelIDsHaveBeenSetIn",
+			svv.PreRelIDsHaveBeenSetIn(tc.ps), tc.expPreRelSet)
+		testhelper.DiffBool(t, tc.IDStr(), "BuildIDsHaveBeenSetIn",
+			svv.BuildIDsHaveBeenSetIn(tc.ps), tc.expBldSet)
+	}
+
+	testhelper.DiffBool(t, "any PSet", "SemVerHasBeenSet",
+		svv.SemVerHasBeenSet(), true)
+	testhelper.DiffBool(t, "any PSet", "PreRelIDsHaveBeenSet",
+		svv.PreRelIDsHaveBeenSet(), false)
+	testhelper.DiffBool(t, "any PSet", "BuildIDsHaveBeenSet",
+		svv.BuildIDsHaveBeenSet(), true)
+}
+
+func TestAddParamsTwice(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		addParams func(svv *semverparams.SemverVals) param.PSetOptFunc
+	}{
+		{
+			ID: testhelper.MkID("semver params"),
+			ExpErr: testhelper.MkExpErr(
+				`the "semver" parameter has already been added to this PSet`),
+			addParams: func(svv *semverparams.SemverVals) param.PSetOptFunc {
+				return svv.AddSemverParam(nil)
+			},
+		},
+		{
+			ID: testhelper.MkID("ID params"),
+			ExpErr: testhelper.MkExpErr(
+				`the "pre-rel-IDs" parameter has already been added`),
+			addParams: func(svv *semverparams.SemverVals) param.PSetOptFunc {
+				return svv.AddIDParams(nil)
+			},
+		},
+		{
+			ID: testhelper.MkID("bump params"),
+			ExpErr: testhelper.MkExpErr(
+				`the "bump" parameter has already been added`),
+			addParams: func(svv *semverparams.SemverVals) param.PSetOptFunc {
+				return svv.AddBumpParams()
+			},
+		},
+		{
+			ID: testhelper.MkID("channel param"),
+			ExpErr: testhelper.MkExpErr(
+				`the "channel" parameter has already been added`),
+			addParams: func(svv *semverparams.SemverVals) param.PSetOptFunc {
+				return svv.AddChannelParam(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			semverparams.AddSemverGroup,
+			tc.addParams(&svv),
+		)
+
+		err := tc.addParams(&svv)(ps)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestGroupRegistration(t *testing.T) {
+	const semverGroupDesc = "common parameters concerned with " + semver.Names
+
+	testCases := []struct {
+		testhelper.ID
+		psof       func() []param.PSetOptFunc
+		expGroup   string
+		expDesc    string
+		expParamGp map[string]string
+	}{
+		{
+			ID: testhelper.MkID("default group, added automatically"),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{}
+
+				return []param.PSetOptFunc{
+					svv.AddSemverParam(nil),
+					svv.AddIDParams(nil),
+					semverparams.AddSemverGroup,
+				}
+			},
+			expGroup: "semver",
+			expDesc:  semverGroupDesc,
+			expParamGp: map[string]string{
+				"semver":      "semver",
+				"pre-rel-IDs": "semver",
+			},
+		},
+		{
+			ID: testhelper.MkID("named group, default description"),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{GroupName: "release"}
+
+				return []param.PSetOptFunc{
+					svv.AddSemverParam(nil),
+					svv.AddBumpParams(),
+				}
+			},
+			expGroup: "release",
+			expDesc:  semverGroupDesc,
+			expParamGp: map[string]string{
+				"semver": "release",
+				"bump":   "release",
+			},
+		},
+		{
+			ID: testhelper.MkID("named group, shared, with description"),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					GroupName: "release",
+					GroupDesc: "the release versions",
+				}
+				other := &semverparams.SemverVals{
+					Prefix:    "prev",
+					GroupName: "release",
+				}
+
+				return []param.PSetOptFunc{
+					svv.AddSemverParam(nil),
+					other.AddSemverParam(nil),
+					semverparams.AddVersionParam,
+				}
+			},
+			expGroup: "release",
+			expDesc:  "the release versions",
+			expParamGp: map[string]string{
+				"semver":      "release",
+				"prev-semver": "release",
+				"version":     "semver",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ps := paramset.NewNoHelpNoExitNoErrRpt(tc.psof()...)
+
+		g, ok := ps.GetGroup(tc.expGroup)
+		if !ok {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: the group %q has not been added", tc.expGroup)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "group description",
+			g.Desc(), tc.expDesc)
+
+		for name, expGroup := range tc.expParamGp {
+			p, err := ps.GetParamByName(name)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: cannot find the parameter %q: %v", name, err)
+
+				continue
+			}
+
+			testhelper.DiffString(t, tc.IDStr(), name+" group",
+				p.GroupName(), expGroup)
+		}
+	}
+}
+
+func TestGroupDescConflict(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		desc string
+	}{
+		{
+			ID:   testhelper.MkID("no description"),
+			desc: "",
+		},
+		{
+			ID:   testhelper.MkID("same description"),
+			desc: "the release versions",
+		},
+		{
+			ID: testhelper.MkID("different description"),
+			ExpErr: testhelper.MkExpErr(`the "release" parameter group` +
+				` has already been added with a different description:` +
+				` "the release versions"`),
+			desc: "the previous release versions",
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{
+			GroupName: "release",
+			GroupDesc: "the release versions",
+		}
+		other := semverparams.SemverVals{
+			Prefix:    "prev",
+			GroupName: "release",
+			GroupDesc: tc.desc,
+		}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(svv.AddSemverParam(nil))
+
+		err := other.AddSemverParam(nil)(ps)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestChecksGroupConfigFiles(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		psof []param.PSetOptFunc
+	}{
+		{
+			ID: testhelper.MkID("group added by the checks"),
+		},
+		{
+			ID: testhelper.MkID("group already added"),
+			psof: []param.PSetOptFunc{
+				func(ps *param.PSet) error {
+					ps.AddGroup("semver-checks", "the checks")
+					return nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svCks := semverparams.SemverChecks{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			append(tc.psof,
+				svCks.AddCheckParams(),
+				svCks.AddChannelTableParams())...)
+
+		g, ok := ps.GetGroup("semver-checks")
+		if !ok {
+			t.Log(tc.IDStr())
+			t.Error("\t: the semver-checks group has not been added")
+
+			continue
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "config files",
+			len(g.ConfigFiles()), 2)
+	}
+}
+
+func TestSemverSource(t *testing.T) {
+	const manifest = "testdata/manifest/npm/package.json"
+
+	testCases := []struct {
+		testhelper.ID
+		attrs   param.Attributes
+		args    []string
+		expSV   string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID:    testhelper.MkID("semver, must be set"),
+			attrs: param.MustBeSet,
+			args:  []string{"-semver", "v1.2.3"},
+			expSV: "v1.2.3",
+		},
+		{
+			ID:    testhelper.MkID("semver-from, must be set"),
+			attrs: param.MustBeSet,
+			args:  []string{"-semver-from", manifest},
+			expSV: "v1.4.0-rc.2",
+		},
+		{
+			ID:    testhelper.MkID("neither, optional"),
+			expSV: "",
+		},
+		{
+			ID:    testhelper.MkID("neither, must be set"),
+			attrs: param.MustBeSet,
+			expSV: "",
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New(`one of the "semver" or "semver-from"` +
+						" parameters must be given"),
+				},
+			},
+		},
+		{
+			ID:    testhelper.MkID("both"),
+			args:  []string{"-semver", "v1.2.3", "-semver-from", manifest},
+			expSV: "v1.4.0-rc.2",
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New(`only one of the "semver" and "semver-from"` +
+						" parameters may be given"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{SemverAttrs: tc.attrs}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(svv.AddSemverParam(nil))
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "semver",
+			svv.SemVer.String(), tc.expSV)
+	}
+}
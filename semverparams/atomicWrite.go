@@ -0,0 +1,38 @@
+package semverparams
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes the content to the named file. The content is first
+// written to a temporary file in the same directory which is then renamed
+// to the target name so that readers see either the old or the new
+// content, never a partial file.
+func writeFileAtomic(filename string, content []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(filename),
+		"."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tmpName := f.Name()
+
+	if _, err = f.Write(content); err == nil {
+		err = f.Chmod(perm)
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmpName, filename)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpName)
+	}
+
+	return err
+}
@@ -0,0 +1,219 @@
+package semverparams
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/internal/gitrepo"
+)
+
+// BuildIDsAuto is the value of the build-IDs parameter which causes the
+// build IDs to be generated from the BuildIDsAutoTemplate
+const BuildIDsAuto = "auto"
+
+// BuildIDsAutoTemplate is the template used to generate the build IDs when
+// the build-IDs parameter is given as "auto". It gives build IDs such as
+// 20240131.142500.1a2b3c4.buildhost
+const BuildIDsAutoTemplate = "{{date}}.{{time}}.{{sha7}}.{{host}}"
+
+// buildIDRepoDir is the directory from which the git repository is found
+// when generating build IDs
+const buildIDRepoDir = "."
+
+// buildIDShortSHALen is the number of characters of the commit name given
+// by the sha7 template function
+const buildIDShortSHALen = 7
+
+// BuildIDSource supplies the values from which build IDs are generated. Any
+// nil function is replaced by the standard one, so the zero value uses the
+// system clock, the environment, the host name and the git repository
+// containing the current directory. The functions can be replaced to give
+// repeatable values in tests.
+type BuildIDSource struct {
+	// Now returns the current time, by default time.Now. The time is
+	// converted to UTC before use.
+	Now func() time.Time
+
+	// Getenv returns the value of the named environment variable, by
+	// default os.Getenv
+	Getenv func(string) string
+
+	// Hostname returns the name of the host, by default os.Hostname
+	Hostname func() (string, error)
+
+	// GitHead returns the full hexadecimal name of the HEAD commit, by
+	// default that of the git repository containing the current directory.
+	// It is read directly from the repository, git is not run.
+	GitHead func() (string, error)
+}
+
+// gitHead returns the name of the HEAD commit of the git repository
+// containing the current directory
+func gitHead() (string, error) {
+	r, err := gitrepo.Find(buildIDRepoDir)
+	if err != nil {
+		return "", err
+	}
+
+	h, err := r.Resolve("HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return h.String(), nil
+}
+
+// withDefaults returns the source with any nil functions replaced by the
+// standard ones
+func (src BuildIDSource) withDefaults() BuildIDSource {
+	if src.Now == nil {
+		src.Now = time.Now
+	}
+
+	if src.Getenv == nil {
+		src.Getenv = os.Getenv
+	}
+
+	if src.Hostname == nil {
+		src.Hostname = os.Hostname
+	}
+
+	if src.GitHead == nil {
+		src.GitHead = gitHead
+	}
+
+	return src
+}
+
+// funcs returns the functions available to a build ID template. The time
+// is taken once so that the date and time are consistent.
+func (src BuildIDSource) funcs() template.FuncMap {
+	now := src.Now().UTC()
+
+	return template.FuncMap{
+		"date": func() string { return now.Format("20060102") },
+		"time": func() string { return now.Format("150405") },
+		"sha":  src.GitHead,
+		"sha7": func() (string, error) {
+			sha, err := src.GitHead()
+			if err != nil {
+				return "", err
+			}
+
+			return sha[:min(len(sha), buildIDShortSHALen)], nil
+		},
+		"host": func() (string, error) {
+			host, err := src.Hostname()
+			if err != nil {
+				return "", err
+			}
+
+			host, _, _ = strings.Cut(host, ".")
+
+			return host, nil
+		},
+		"env": src.Getenv,
+	}
+}
+
+// IsBuildIDTemplate returns true if the value of the build-IDs parameter
+// is to be used to generate the build IDs rather than as the IDs
+// themselves, that is if it is "auto" or contains a template action
+func IsBuildIDTemplate(val string) bool {
+	return val == BuildIDsAuto || strings.Contains(val, "{{")
+}
+
+// GenerateBuildIDs generates build IDs from the template, taking the values
+// from the source. The template may be "auto", in which case the
+// BuildIDsAutoTemplate is used, or else a text/template which may use the
+// following functions:
+//
+//	date       the date as YYYYMMDD (UTC)
+//	time       the time as HHMMSS (UTC)
+//	sha        the full name of the git HEAD commit
+//	sha7       the first 7 characters of the name of the git HEAD commit
+//	host       the host name, up to the first '.'
+//	env "VAR"  the value of the environment variable VAR
+//
+// The result is split into IDs at each '.' and each ID is checked with
+// semver.CheckBuildID. For instance, '{{date}}.{{sha7}}' gives build IDs
+// such as 20240131.1a2b3c4.
+func GenerateBuildIDs(tmplText string, src BuildIDSource) ([]string, error) {
+	if tmplText == BuildIDsAuto {
+		tmplText = BuildIDsAutoTemplate
+	}
+
+	src = src.withDefaults()
+
+	tmpl, err := template.New("build-IDs").
+		Funcs(src.funcs()).
+		Parse(tmplText)
+	if err != nil {
+		return nil, fmt.Errorf("bad build ID template: %w", err)
+	}
+
+	var b strings.Builder
+
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return nil, fmt.Errorf("cannot generate the build IDs: %w", err)
+	}
+
+	ids := strings.Split(b.String(), ".")
+	for _, id := range ids {
+		if err := semver.CheckBuildID(id); err != nil {
+			return nil, fmt.Errorf("the generated build IDs (%q) are invalid: %w",
+				b.String(), err)
+		}
+	}
+
+	return ids, nil
+}
+
+// BuildIDsSetter is a parameter setter for a list of build IDs (as made by
+// IDListSetter) which will also generate the IDs if the value is "auto" or
+// a template (see GenerateBuildIDs)
+type BuildIDsSetter struct {
+	psetter.StrList[string]
+
+	// Source supplies the values used to generate the build IDs, it may be
+	// nil in which case the standard values are used
+	Source *BuildIDSource
+}
+
+// SetWithVal generates the build IDs if the parameter value is "auto" or a
+// template, otherwise it sets the IDs from the value. Only if the IDs are
+// valid is the Value set.
+func (bis BuildIDsSetter) SetWithVal(paramName, paramVal string) error {
+	if !IsBuildIDTemplate(paramVal) {
+		return bis.StrList.SetWithVal(paramName, paramVal)
+	}
+
+	var src BuildIDSource
+	if bis.Source != nil {
+		src = *bis.Source
+	}
+
+	ids, err := GenerateBuildIDs(paramVal, src)
+	if err != nil {
+		return err
+	}
+
+	*bis.Value = ids
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (bis BuildIDsSetter) AllowedValues() string {
+	return bis.StrList.AllowedValues() +
+		". Alternatively, '" + BuildIDsAuto + "' to generate the IDs" +
+		" (as " + BuildIDsAutoTemplate + ") or a template using" +
+		" the functions: date (YYYYMMDD), time (HHMMSS), sha and sha7" +
+		" (the name of the git HEAD commit), host and env \"VAR\"." +
+		" For instance, '{{date}}.{{sha7}}'"
+}
@@ -0,0 +1,176 @@
+package semverparams_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// testBuildIDSource returns a BuildIDSource giving fixed values
+func testBuildIDSource() semverparams.BuildIDSource {
+	return semverparams.BuildIDSource{
+		Now: func() time.Time {
+			return time.Date(2024, 1, 31, 14, 25, 0, 0,
+				time.FixedZone("EST", -5*60*60)) //nolint:mnd
+		},
+		Getenv: func(name string) string {
+			return map[string]string{
+				"CI_JOB":  "1234",
+				"BAD_VAL": "a_b",
+			}[name]
+		},
+		Hostname: func() (string, error) {
+			return "buildhost.example.com", nil
+		},
+		GitHead: func() (string, error) {
+			return "1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d", nil
+		},
+	}
+}
+
+func TestGenerateBuildIDs(t *testing.T) {
+	noRepo := testBuildIDSource()
+	noRepo.GitHead = func() (string, error) {
+		return "", errors.New("no git repository found")
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		tmpl   string
+		src    semverparams.BuildIDSource
+		expIDs []string
+	}{
+		{
+			ID:     testhelper.MkID("auto"),
+			tmpl:   semverparams.BuildIDsAuto,
+			src:    testBuildIDSource(),
+			expIDs: []string{"20240131", "192500", "1a2b3c4", "buildhost"},
+		},
+		{
+			ID:     testhelper.MkID("template"),
+			tmpl:   "{{date}}.{{sha7}}",
+			src:    testBuildIDSource(),
+			expIDs: []string{"20240131", "1a2b3c4"},
+		},
+		{
+			ID:   testhelper.MkID("full sha and env"),
+			tmpl: `{{sha}}.job{{env "CI_JOB"}}`,
+			src:  testBuildIDSource(),
+			expIDs: []string{
+				"1a2b3c4d5e6f7a8b9c0d1a2b3c4d5e6f7a8b9c0d", "job1234",
+			},
+		},
+		{
+			ID:     testhelper.MkID("no git repo, sha not used"),
+			tmpl:   "{{date}}.{{time}}",
+			src:    noRepo,
+			expIDs: []string{"20240131", "192500"},
+		},
+		{
+			ID: testhelper.MkID("no git repo"),
+			ExpErr: testhelper.MkExpErr("cannot generate the build IDs: ",
+				"no git repository found"),
+			tmpl: "{{date}}.{{sha7}}",
+			src:  noRepo,
+		},
+		{
+			ID:     testhelper.MkID("bad template"),
+			ExpErr: testhelper.MkExpErr("bad build ID template: "),
+			tmpl:   "{{date",
+			src:    testBuildIDSource(),
+		},
+		{
+			ID:     testhelper.MkID("unknown function"),
+			ExpErr: testhelper.MkExpErr("bad build ID template: ", "nonesuch"),
+			tmpl:   "{{nonesuch}}",
+			src:    testBuildIDSource(),
+		},
+		{
+			ID: testhelper.MkID("bad env value"),
+			ExpErr: testhelper.MkExpErr(
+				`the generated build IDs ("20240131.a_b") are invalid`),
+			tmpl: `{{date}}.{{env "BAD_VAL"}}`,
+			src:  testBuildIDSource(),
+		},
+		{
+			ID: testhelper.MkID("empty env value"),
+			ExpErr: testhelper.MkExpErr(
+				`the generated build IDs ("20240131.") are invalid`),
+			tmpl: `{{date}}.{{env "UNSET"}}`,
+			src:  testBuildIDSource(),
+		},
+	}
+
+	for _, tc := range testCases {
+		ids, err := semverparams.GenerateBuildIDs(tc.tmpl, tc.src)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffStringSlice(t, tc.IDStr(), "build IDs",
+				ids, tc.expIDs)
+		}
+	}
+}
+
+func TestBuildIDsParam(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		args    []string
+		expIDs  string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID:     testhelper.MkID("explicit IDs"),
+			args:   []string{"-build-IDs", "build.007"},
+			expIDs: "build.007",
+		},
+		{
+			ID:     testhelper.MkID("auto"),
+			args:   []string{"-build-IDs", "auto"},
+			expIDs: "20240131.192500.1a2b3c4.buildhost",
+		},
+		{
+			ID:     testhelper.MkID("template"),
+			args:   []string{"-build-IDs", "ci.{{env \"CI_JOB\"}}.{{sha7}}"},
+			expIDs: "ci.1234.1a2b3c4",
+		},
+		{
+			ID:   testhelper.MkID("bad generated IDs"),
+			args: []string{"-build-IDs", `{{env "BAD_VAL"}}`},
+			expErrs: errutil.ErrMap{
+				"build-IDs": []error{
+					errors.New(`the generated build IDs ("a_b") are invalid:` +
+						` the Build ID: 'a_b' must be a non-empty string` +
+						` of letters, digits or hyphens` +
+						"\nAt: [command line]: Supplied Parameter:2:" +
+						` "-build-IDs" "{{env \"BAD_VAL\"}}"`),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{BuildIDSource: testBuildIDSource()}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			svv.AddIDParams(nil),
+		)
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "build IDs",
+			strings.Join(svv.BuildIDs, "."), tc.expIDs)
+	}
+}
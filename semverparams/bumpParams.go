@@ -0,0 +1,207 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// These are the values that can be given to the bump parameter
+const (
+	bumpValMajor = "major"
+	bumpValMinor = "minor"
+	bumpValPatch = "patch"
+	bumpValAuto  = "auto"
+)
+
+// bumpByName maps the bump parameter values to the corresponding Bump
+var bumpByName = map[string]Bump{
+	bumpValMajor: BumpMajor,
+	bumpValMinor: BumpMinor,
+	bumpValPatch: BumpPatch,
+}
+
+// bumpSpec records the values of the bump parameters
+type bumpSpec struct {
+	bump        string
+	commitsFile string
+	gitRevs     string
+}
+
+// AddBumpParams returns a function that will add parameters for bumping the
+// SemVer to the passed PSet. The bump can be given explicitly or inferred
+// from Conventional Commits messages read from a file, the standard input
+// or the git repository containing the current directory. The bump is
+// applied to the SemVer in a final check, so the SemVer must also be given.
+// The final checks are run in the order in which they are added so, if the
+// IDs of the SemVer are to be checked (see AddSemverParam), these
+// parameters should be added first so that the bumped version is checked.
+func (svv *SemverVals) AddBumpParams() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svv.Prefix != "" {
+			prefix = svv.Prefix + "-"
+		}
+
+		var (
+			bumpPN = svv.BumpParam.resolve(prefix,
+				"bump", nil,
+				"specify how the "+semver.Name+" should be incremented")
+			bumpCommitsPN = svv.BumpCommitsParam.resolve(prefix,
+				"bump-commits", nil,
+				"specify a file from which to read the commit messages"+
+					" used to infer the increment when the "+
+					bumpPN.name+" parameter is '"+bumpValAuto+"'. If the name is '-'"+
+					" the messages are read from the standard input."+
+					" The messages should be separated by NUL characters,"+
+					" otherwise each line is taken as a message")
+			bumpGitRevsPN = svv.BumpGitRevsParam.resolve(prefix,
+				"bump-git-revs", nil,
+				"specify the range of commits (as FROM..TO) in the"+
+					" git repository containing the current directory"+
+					" whose messages are used to infer the increment"+
+					" when the "+bumpPN.name+" parameter is '"+
+					bumpValAuto+"'. If TO is omitted HEAD is used")
+		)
+
+		pp := svv.paramsFor(ps)
+		if pp.bump != nil {
+			return fmt.Errorf("the %q parameter has already been added"+
+				" to this PSet", bumpPN.name)
+		}
+
+		err := checkParamNames(ps, bumpPN, bumpCommitsPN, bumpGitRevsPN)
+		if err != nil {
+			return err
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		spec := &bumpSpec{}
+
+		pp.bump = ps.Add(bumpPN.name,
+			psetter.Enum[string]{
+				Value: &spec.bump,
+				AllowedVals: psetter.AllowedVals[string]{
+					bumpValMajor: "increment the major version",
+					bumpValMinor: "increment the minor version",
+					bumpValPatch: "increment the patch version",
+					bumpValAuto: "infer the increment from" +
+						" Conventional Commits messages",
+				},
+				AllowInvalidInitialValue: true,
+			},
+			bumpPN.help,
+			param.AltNames(bumpPN.altNames...),
+			param.PostAction(bumpPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.SeeAlso(bumpCommitsPN.name, bumpGitRevsPN.name),
+		)
+
+		ps.Add(bumpCommitsPN.name,
+			psetter.String[string]{Value: &spec.commitsFile},
+			bumpCommitsPN.help,
+			param.AltNames(bumpCommitsPN.altNames...),
+			param.PostAction(bumpCommitsPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.SeeAlso(bumpPN.name),
+		)
+
+		ps.Add(bumpGitRevsPN.name,
+			psetter.String[string]{Value: &spec.gitRevs},
+			bumpGitRevsPN.help,
+			param.AltNames(bumpGitRevsPN.altNames...),
+			param.PostAction(bumpGitRevsPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.SeeAlso(bumpPN.name),
+		)
+
+		ps.AddFinalCheck(applyBump(svv, pp, spec, bumpPN.name))
+
+		return nil
+	}
+}
+
+// applyBump applies the requested bump to the semver, recording the
+// version before the bump
+func applyBump(
+	svv *SemverVals, pp *psetParams, spec *bumpSpec, bumpParamName string,
+) param.FinalCheckFunc {
+	return func() error {
+		if spec.bump == "" {
+			return nil
+		}
+
+		errPfx := ""
+
+		if svv.Desc != "" {
+			errPfx = svv.Desc + ": "
+		}
+
+		if !svv.SemVerHasBeenSet() {
+			return fmt.Errorf("%sthe %s cannot be bumped as it has not been set",
+				errPfx, semver.Name)
+		}
+
+		bump, ok := bumpByName[spec.bump]
+		if !ok {
+			msgs, err := spec.commitMsgs(bumpParamName)
+			if err != nil {
+				return fmt.Errorf("%s%w", errPfx, err)
+			}
+
+			bump, svv.BumpReasons = InferBump(msgs)
+		}
+
+		if bump == BumpNone {
+			return nil
+		}
+
+		pp.bumpedFrom = &semver.SV{}
+		svv.SemVer.CopyInto(pp.bumpedFrom)
+
+		bump.Apply(&svv.SemVer)
+
+		return nil
+	}
+}
+
+// commitMsgs returns the commit messages from the source given by the bump
+// parameters
+func (spec bumpSpec) commitMsgs(bumpParamName string) ([]CommitMsg, error) {
+	switch {
+	case spec.commitsFile != "" && spec.gitRevs != "":
+		return nil, fmt.Errorf(
+			"the commit messages for '-%s %s' must come from"+
+				" either a file or a git repository, not both",
+			bumpParamName, bumpValAuto)
+	case spec.commitsFile == "-":
+		return ReadCommitMsgs(os.Stdin)
+	case spec.commitsFile != "":
+		f, err := os.Open(spec.commitsFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		return ReadCommitMsgs(f)
+	case spec.gitRevs != "":
+		from, to, _ := strings.Cut(spec.gitRevs, "..")
+		if to == "" {
+			to = "HEAD"
+		}
+
+		return GitCommitMsgs(".", from, to)
+	}
+
+	return nil, errors.New("there is no source of commit messages for '-" +
+		bumpParamName + " " + bumpValAuto + "'")
+}
@@ -0,0 +1,195 @@
+package semverparams
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ChangelogEntry records the details of a release heading in a changelog
+// in the Keep a Changelog format (see https://keepachangelog.com)
+type ChangelogEntry struct {
+	// Version is the released version. It is not set for the Unreleased
+	// entry.
+	Version semver.SV
+	// Unreleased is set for the entry recording unreleased changes
+	Unreleased bool
+	// Date is the release date. It is the zero time if no date is given.
+	Date time.Time
+	// Line is the line number (starting at 1) of the heading
+	Line int
+}
+
+// changelogHeadingRE matches a release heading in a changelog. The
+// submatches are the version (or "Unreleased") and the date, if any
+var changelogHeadingRE = regexp.MustCompile(
+	`^##\s+\[?([^\]\s]+)\]?(?:\s+-\s+(\S+))?(?:\s+\[YANKED\])?\s*$`)
+
+// changelogUnreleased is the name of the changelog entry recording the
+// unreleased changes
+const changelogUnreleased = "Unreleased"
+
+// changelogDateFmt is the format of the release date in a changelog heading
+const changelogDateFmt = time.DateOnly
+
+// ReadChangelog reads the named changelog file and returns the entries it
+// contains.
+func ReadChangelog(filename string) ([]ChangelogEntry, error) {
+	content, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseChangelog(filename, content)
+}
+
+// ParseChangelog finds the release headings in the content of a changelog
+// in the Keep a Changelog format and returns an entry for each, in the
+// order in which they appear. Release headings are level 2 headings such
+// as:
+//
+//	## [v1.2.3] - 2026-01-01
+//	## [Unreleased]
+//
+// The leading 'v' of the version is optional. The filename is used to give
+// context in any error.
+func ParseChangelog(filename string, content []byte) ([]ChangelogEntry, error) {
+	var entries []ChangelogEntry
+
+	for i, l := range manifestLines(content) {
+		if !strings.HasPrefix(l.text, "## ") {
+			continue
+		}
+
+		lineNum := i + 1
+
+		parts := changelogHeadingRE.FindStringSubmatch(l.text)
+		if parts == nil {
+			return nil, fmt.Errorf("%s:%d: bad release heading: %q",
+				filename, lineNum, l.text)
+		}
+
+		entry := ChangelogEntry{Line: lineNum}
+
+		if strings.EqualFold(parts[1], changelogUnreleased) {
+			entry.Unreleased = true
+		} else if err := setFromVersionText(&entry.Version, parts[1]); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, lineNum, err)
+		}
+
+		if parts[2] != "" {
+			d, err := time.Parse(changelogDateFmt, parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: bad release date: %q",
+					filename, lineNum, parts[2])
+			}
+
+			entry.Date = d
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// ChangelogReleases returns just the entries for released versions
+func ChangelogReleases(entries []ChangelogEntry) []ChangelogEntry {
+	var released []ChangelogEntry
+
+	for _, e := range entries {
+		if !e.Unreleased {
+			released = append(released, e)
+		}
+	}
+
+	return released
+}
+
+// LatestChangelogRelease returns the entry for the newest released version,
+// that is the released version with the highest precedence. The Unreleased
+// entry is ignored. It returns an error if there are no released versions;
+// the filename is used to give context in the error.
+func LatestChangelogRelease(
+	filename string, entries []ChangelogEntry,
+) (ChangelogEntry, error) {
+	released := ChangelogReleases(entries)
+	if len(released) == 0 {
+		return ChangelogEntry{},
+			fmt.Errorf("%s: there are no released versions in the changelog",
+				filename)
+	}
+
+	latest := released[0]
+
+	for _, e := range released[1:] {
+		if semver.Less(&latest.Version, &e.Version) {
+			latest = e
+		}
+	}
+
+	return latest, nil
+}
+
+// AddChangelogCheck returns a function that will add a final check to the
+// passed PSet. If the SemVer has been set, the check confirms that the named
+// changelog file has an entry for it and that it is newer than every other
+// released version in the changelog.
+func (svv *SemverVals) AddChangelogCheck(filename string) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.AddFinalCheck(checkChangelog(svv, filename))
+
+		return nil
+	}
+}
+
+// checkChangelog checks that the changelog has an entry for the semver and
+// that it is newer than all the other released entries
+func checkChangelog(svv *SemverVals, filename string) param.FinalCheckFunc {
+	return func() error {
+		if !svv.SemVerHasBeenSet() {
+			return nil
+		}
+
+		errPfx := ""
+
+		if svv.Desc != "" {
+			errPfx = svv.Desc + ": "
+		}
+
+		entries, err := ReadChangelog(filename)
+		if err != nil {
+			return fmt.Errorf("%s%w", errPfx, err)
+		}
+
+		found := false
+
+		for _, e := range ChangelogReleases(entries) {
+			switch svCompare(&e.Version, &svv.SemVer) {
+			case 0:
+				if found {
+					return fmt.Errorf("%s%s:%d: duplicate changelog entry for %s",
+						errPfx, filename, e.Line, svv.SemVer)
+				}
+
+				found = true
+			case 1:
+				return fmt.Errorf("%s%s:%d: changelog entry %s"+
+					" is newer than %s",
+					errPfx, filename, e.Line, e.Version, svv.SemVer)
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%s%s: there is no changelog entry for %s",
+				errPfx, filename, svv.SemVer)
+		}
+
+		return nil
+	}
+}
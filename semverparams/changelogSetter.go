@@ -0,0 +1,57 @@
+package semverparams
+
+import (
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ChangelogSetter is a parameter setter which will set a semantic version
+// number from the newest released version in a changelog in the Keep a
+// Changelog format. The parameter value is the name of the changelog file.
+// It satisfies the param.Setter interface and so can be used when
+// specifying a command line argument using the param package.
+type ChangelogSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *semver.SV
+}
+
+// SetWithVal reads the named changelog file and finds the newest released
+// version (see LatestChangelogRelease). It returns an error if the file
+// cannot be read or parsed or if it has no released versions. Only if
+// there is no error is the Value set.
+func (cs ChangelogSetter) SetWithVal(_ string, paramVal string) error {
+	entries, err := ReadChangelog(paramVal)
+	if err != nil {
+		return err
+	}
+
+	latest, err := LatestChangelogRelease(paramVal, entries)
+	if err != nil {
+		return err
+	}
+
+	latest.Version.CopyInto(cs.Value)
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (cs ChangelogSetter) AllowedValues() string {
+	return "the name of a changelog file in the Keep a Changelog format." +
+		" The " + semver.Name + " is taken from the newest released" +
+		" version, the Unreleased entry is ignored." +
+		" The leading 'v' of the version is optional."
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (cs ChangelogSetter) CurrentValue() string {
+	return cs.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (cs ChangelogSetter) CheckSetter(name string) {
+	if cs.Value == nil {
+		panic(name + ": ChangelogSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,232 @@
+package semverparams_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestReadChangelog(t *testing.T) {
+	entries, err := semverparams.ReadChangelog(
+		"testdata/changelog/CHANGELOG.md")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	expEntries := []struct {
+		version    string
+		unreleased bool
+		date       string
+		line       int
+	}{
+		{unreleased: true, line: 5},
+		{version: "v1.3.0", date: "2026-02-01", line: 11},
+		{version: "v1.2.0", date: "2026-01-01", line: 17},
+		{version: "v1.1.0-rc.1", date: "2025-12-01", line: 19},
+	}
+
+	if testhelper.DiffInt(t, "ReadChangelog", "entry count",
+		len(entries), len(expEntries)) {
+		return
+	}
+
+	for i, exp := range expEntries {
+		e := entries[i]
+		id := e.Version.String()
+
+		testhelper.DiffString(t, id, "version", e.Version.String(), exp.version)
+		testhelper.DiffBool(t, id, "unreleased", e.Unreleased, exp.unreleased)
+		testhelper.DiffInt(t, id, "line", e.Line, exp.line)
+
+		expDate := time.Time{}
+		if exp.date != "" {
+			expDate, _ = time.Parse(time.DateOnly, exp.date)
+		}
+
+		testhelper.DiffTime(t, id, "date", e.Date, expDate)
+	}
+
+	testhelper.DiffInt(t, "ChangelogReleases", "count",
+		len(semverparams.ChangelogReleases(entries)), 3)
+
+	badChangelogTests := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		content string
+	}{
+		{
+			ID:      testhelper.MkID("bad heading"),
+			ExpErr:  testhelper.MkExpErr(`CL.md:2: bad release heading: "## a b"`),
+			content: "# Changelog\n## a b\n",
+		},
+		{
+			ID:      testhelper.MkID("bad version"),
+			ExpErr:  testhelper.MkExpErr("CL.md:1: bad semantic version ID"),
+			content: "## [1.2] - 2026-01-01\n",
+		},
+		{
+			ID:      testhelper.MkID("bad date"),
+			ExpErr:  testhelper.MkExpErr(`CL.md:1: bad release date: "1/1/26"`),
+			content: "## [1.2.0] - 1/1/26\n",
+		},
+	}
+
+	for _, tc := range badChangelogTests {
+		_, err := semverparams.ParseChangelog("CL.md", []byte(tc.content))
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestChangelogCheck(t *testing.T) {
+	const changelog = "testdata/changelog/CHANGELOG.md"
+
+	testCases := []struct {
+		testhelper.ID
+		args    []string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID: testhelper.MkID("no semver"),
+		},
+		{
+			ID:   testhelper.MkID("latest entry"),
+			args: []string{"-semver", "v1.3.0+build.1"},
+		},
+		{
+			ID:   testhelper.MkID("older entry"),
+			args: []string{"-semver", "v1.2.0"},
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New(changelog + ":11: changelog entry v1.3.0" +
+						" is newer than v1.2.0"),
+				},
+			},
+		},
+		{
+			ID:   testhelper.MkID("no entry"),
+			args: []string{"-semver", "v1.4.0"},
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New(changelog +
+						": there is no changelog entry for v1.4.0"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			semverparams.AddSemverGroup,
+			svv.AddSemverParam(nil),
+			svv.AddChangelogCheck(changelog),
+		)
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+	}
+}
+
+func TestLatestChangelogRelease(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		content string
+		expSV   string
+		expLine int
+	}{
+		{
+			ID:      testhelper.MkID("newest first"),
+			content: "## [Unreleased]\n## [1.3.0]\n## [1.2.0]\n",
+			expSV:   "v1.3.0",
+			expLine: 2,
+		},
+		{
+			ID:      testhelper.MkID("newest not first"),
+			content: "## [v1.2.0]\n## [v2.0.0-rc.1]\n## [v1.10.0]\n",
+			expSV:   "v2.0.0-rc.1",
+			expLine: 2,
+		},
+		{
+			ID: testhelper.MkID("unreleased only"),
+			ExpErr: testhelper.MkExpErr(
+				"CL.md: there are no released versions in the changelog"),
+			content: "# Changelog\n## [Unreleased]\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		entries, err := semverparams.ParseChangelog("CL.md",
+			[]byte(tc.content))
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": unexpected error:", err)
+		}
+
+		latest, err := semverparams.LatestChangelogRelease("CL.md", entries)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "version",
+				latest.Version.String(), tc.expSV)
+			testhelper.DiffInt(t, tc.IDStr(), "line", latest.Line, tc.expLine)
+		}
+	}
+}
+
+func TestChangelogSetter(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		args    []string
+		expSV   string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID:    testhelper.MkID("good changelog"),
+			args:  []string{"-changelog", "testdata/changelog/CHANGELOG.md"},
+			expSV: "v1.3.0",
+		},
+		{
+			ID:   testhelper.MkID("missing changelog"),
+			args: []string{"-changelog", "testdata/changelog/nonesuch.md"},
+			expErrs: errutil.ErrMap{
+				"changelog": []error{
+					errors.New("open testdata/changelog/nonesuch.md:" +
+						" no such file or directory" +
+						"\nAt: [command line]: Supplied Parameter:2:" +
+						` "-changelog" "testdata/changelog/nonesuch.md"`),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var sv semver.SV
+
+		ps := paramset.NewNoHelpNoExitNoErrRpt()
+		ps.Add("changelog", semverparams.ChangelogSetter{Value: &sv},
+			"set the version from the changelog")
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "semver", sv.String(), tc.expSV)
+	}
+}
@@ -0,0 +1,374 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+const (
+	// channelNumber is the ID in the Shape of a Channel which matches any
+	// number. It cannot appear in a pre-release ID so there is no
+	// ambiguity.
+	channelNumber = "#"
+	// channelFirstNumber is the number used when making the pre-release
+	// IDs for a Channel
+	channelFirstNumber = "1"
+
+	channelSep           = ","
+	channelShapeSep      = "="
+	channelTransitionSep = ">"
+)
+
+// Channel describes a release channel and the pre-release IDs of the
+// versions released through it
+type Channel struct {
+	Name string
+
+	// Shape gives the pre-release IDs of the versions in the channel. An ID
+	// of "#" matches any number, any other ID must match exactly. For
+	// instance, the Shape ["beta", "#"] matches versions such as
+	// v1.2.3-beta.4. An empty Shape is for versions with no pre-release IDs
+	// (stable releases).
+	Shape []string
+}
+
+// String returns the channel in the form used by the channels parameter
+func (c Channel) String() string {
+	return c.Name + channelShapeSep + strings.Join(c.Shape, ".")
+}
+
+// matches returns true if the pre-release IDs have the shape of the channel
+func (c Channel) matches(ids []string) bool {
+	if len(ids) != len(c.Shape) {
+		return false
+	}
+
+	for i, s := range c.Shape {
+		if s == channelNumber {
+			if strings.Trim(ids[i], "0123456789") != "" {
+				return false
+			}
+
+			continue
+		}
+
+		if ids[i] != s {
+			return false
+		}
+	}
+
+	return true
+}
+
+// preRelIDs returns the first pre-release IDs of the channel, any number
+// in the Shape is given as 1
+func (c Channel) preRelIDs() []string {
+	ids := slices.Clone(c.Shape)
+	for i, s := range ids {
+		if s == channelNumber {
+			ids[i] = channelFirstNumber
+		}
+	}
+
+	return ids
+}
+
+// ChannelTable gives the release channels, in order of increasing
+// stability, and the moves allowed between them.
+type ChannelTable struct {
+	Channels []Channel
+
+	// Transitions maps the name of a channel to the names of the channels
+	// to which a version in that channel may move. If it is nil a version
+	// may move to any later channel. A version in a channel with an empty
+	// Shape (a stable release) may move to any channel as it is taken to
+	// start a new release cycle.
+	Transitions map[string][]string
+}
+
+// DefaultChannelTable returns the standard table of release channels:
+// alpha, beta and rc (with pre-release IDs such as alpha.1, beta.2 and
+// rc.3) and stable (with no pre-release IDs).
+func DefaultChannelTable() ChannelTable {
+	return ChannelTable{
+		Channels: []Channel{
+			{Name: "alpha", Shape: []string{"alpha", channelNumber}},
+			{Name: "beta", Shape: []string{"beta", channelNumber}},
+			{Name: "rc", Shape: []string{"rc", channelNumber}},
+			{Name: "stable"},
+		},
+	}
+}
+
+// ParseChannels parses the string as a list of release channels. The
+// channels are separated by ',' and each is a name, optionally followed by
+// '=' and the Shape of its pre-release IDs, separated by '.'. If the Shape is
+// not given it is the name followed by a number ("#"). For instance,
+// "alpha,beta,rc=rc.#,stable=" gives the default channels.
+func ParseChannels(s string) ([]Channel, error) {
+	var channels []Channel
+
+	for part := range strings.SplitSeq(s, channelSep) {
+		name, shape, hasShape := strings.Cut(strings.TrimSpace(part),
+			channelShapeSep)
+
+		if err := checkChannelName(name); err != nil {
+			return nil, err
+		}
+
+		c := Channel{Name: name}
+
+		switch {
+		case !hasShape:
+			c.Shape = []string{name, channelNumber}
+		case shape != "":
+			c.Shape = strings.Split(shape, ".")
+		}
+
+		for _, id := range c.Shape {
+			if id == channelNumber {
+				continue
+			}
+
+			if err := semver.CheckPreRelID(id); err != nil {
+				return nil, fmt.Errorf("bad release channel %q: %w", name, err)
+			}
+		}
+
+		for _, prev := range channels {
+			if prev.Name == c.Name {
+				return nil, fmt.Errorf("the release channel %q is repeated",
+					name)
+			}
+
+			if slices.Equal(prev.Shape, c.Shape) {
+				return nil, fmt.Errorf(
+					"the release channels %q and %q have the same shape",
+					prev.Name, name)
+			}
+		}
+
+		channels = append(channels, c)
+	}
+
+	return channels, nil
+}
+
+// checkChannelName returns an error if the name is not a valid release
+// channel name: a non-empty string of letters, digits or hyphens
+func checkChannelName(name string) error {
+	const nameChars = "-0123456789" +
+		"abcdefghijklmnopqrstuvwxyz" +
+		"ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+	if name == "" || strings.Trim(name, nameChars) != "" {
+		return fmt.Errorf("bad release channel name %q:"+
+			" it must be a non-empty string of letters, digits or hyphens",
+			name)
+	}
+
+	return nil
+}
+
+// ParseChannelTransitions parses the string as a list of the moves allowed
+// between release channels. The moves are separated by ',' and each is the
+// name of a channel followed by '>' and the name of the channel to which a
+// version may move. For instance, "alpha>beta,beta>rc,rc>stable".
+func ParseChannelTransitions(s string) (map[string][]string, error) {
+	transitions := map[string][]string{}
+
+	for part := range strings.SplitSeq(s, channelSep) {
+		from, to, ok := strings.Cut(strings.TrimSpace(part),
+			channelTransitionSep)
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("bad release channel transition %q:"+
+				" it should be of the form from"+channelTransitionSep+"to",
+				part)
+		}
+
+		if !slices.Contains(transitions[from], to) {
+			transitions[from] = append(transitions[from], to)
+		}
+	}
+
+	return transitions, nil
+}
+
+// orDefault returns the table or, if it is nil or has no channels, the
+// DefaultChannelTable
+func (ct *ChannelTable) orDefault() ChannelTable {
+	if ct == nil || len(ct.Channels) == 0 {
+		return DefaultChannelTable()
+	}
+
+	return *ct
+}
+
+// channelNames returns the names of the channels
+func (ct ChannelTable) channelNames() []string {
+	names := make([]string, 0, len(ct.Channels))
+	for _, c := range ct.Channels {
+		names = append(names, c.Name)
+	}
+
+	return names
+}
+
+// find returns the index of the named channel or -1 if there is no such
+// channel
+func (ct ChannelTable) find(name string) int {
+	return slices.IndexFunc(ct.Channels, func(c Channel) bool {
+		return c.Name == name
+	})
+}
+
+// Check returns an error if the table has no channels or if any of the
+// Transitions refers to a channel not in the table
+func (ct ChannelTable) Check() error {
+	if len(ct.Channels) == 0 {
+		return errors.New("there are no release channels")
+	}
+
+	for _, from := range slices.Sorted(maps.Keys(ct.Transitions)) {
+		for _, name := range append([]string{from}, ct.Transitions[from]...) {
+			if ct.find(name) < 0 {
+				return fmt.Errorf("the release channel transitions refer to"+
+					" an unknown channel: %q", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ChannelOf returns the channel of the semantic version number, that is
+// the first channel whose Shape matches its pre-release IDs. It returns an
+// error if there is no such channel.
+func (ct ChannelTable) ChannelOf(sv *semver.SV) (Channel, error) {
+	for _, c := range ct.Channels {
+		if c.matches(sv.PreRelIDs()) {
+			return c, nil
+		}
+	}
+
+	return Channel{}, fmt.Errorf("%s is not in any release channel (%s)",
+		sv, strings.Join(ct.channelNames(), ", "))
+}
+
+// CanMove returns true if a version in the from channel may move to the to
+// channel
+func (ct ChannelTable) CanMove(from, to string) bool {
+	fromIdx, toIdx := ct.find(from), ct.find(to)
+	if fromIdx < 0 || toIdx < 0 {
+		return false
+	}
+
+	if from == to || len(ct.Channels[fromIdx].Shape) == 0 {
+		return true
+	}
+
+	if ct.Transitions == nil {
+		return toIdx > fromIdx
+	}
+
+	return slices.Contains(ct.Transitions[from], to)
+}
+
+// Move moves the semantic version number to the named channel. If it is
+// already in the channel it is unchanged, otherwise its pre-release IDs are
+// replaced with the first pre-release IDs of the new channel. A version in a
+// stable channel (one with an empty Shape) has its patch version
+// incremented first as it starts a new release cycle, so v1.2.3 moved to the
+// "alpha" channel becomes v1.2.4-alpha.1. It returns an error if the
+// channel is not in the table, if the version is not in any channel, if the
+// move is not allowed or if the result would not be a later version.
+func (ct ChannelTable) Move(sv *semver.SV, to string) error {
+	return ct.moveAfter(sv, to, nil)
+}
+
+// moveAfter moves the semantic version number to the named channel as for
+// Move. If bumpedFrom is not nil the version has already been bumped from
+// it to start a new release cycle; the version is not bumped again and the
+// result must be later than bumpedFrom.
+func (ct ChannelTable) moveAfter(sv *semver.SV, to string,
+	bumpedFrom *semver.SV,
+) error {
+	toIdx := ct.find(to)
+	if toIdx < 0 {
+		return fmt.Errorf("unknown release channel %q (it should be one of: %s)",
+			to, strings.Join(ct.channelNames(), ", "))
+	}
+
+	from, err := ct.ChannelOf(sv)
+	if err != nil {
+		return err
+	}
+
+	if from.Name == to {
+		return nil
+	}
+
+	if !ct.CanMove(from.Name, to) {
+		return fmt.Errorf("%s cannot move from the %q release channel to %q",
+			sv, from.Name, to)
+	}
+
+	var orig semver.SV
+
+	sv.CopyInto(&orig)
+
+	earlier := &orig
+	if bumpedFrom != nil {
+		earlier = bumpedFrom
+	} else if len(from.Shape) == 0 {
+		sv.IncrPatch()
+	}
+
+	ids := ct.Channels[toIdx].preRelIDs()
+	if len(ids) == 0 {
+		sv.ClearPreRelIDs()
+	} else if err := sv.SetPreRelIDs(ids); err != nil {
+		orig.CopyInto(sv)
+
+		return err
+	}
+
+	if !semver.Less(earlier, sv) {
+		orig.CopyInto(sv)
+
+		return fmt.Errorf("moving %s from the %q release channel to %q"+
+			" would not give a later version", sv, from.Name, to)
+	}
+
+	return nil
+}
+
+// ChannelsString returns the channels in the form used by the channels
+// parameter
+func (ct ChannelTable) ChannelsString() string {
+	parts := make([]string, 0, len(ct.Channels))
+	for _, c := range ct.Channels {
+		parts = append(parts, c.String())
+	}
+
+	return strings.Join(parts, channelSep)
+}
+
+// TransitionsString returns the Transitions in the form used by the
+// channel-transitions parameter. It is empty if the Transitions are nil.
+func (ct ChannelTable) TransitionsString() string {
+	var parts []string
+
+	for _, from := range ct.channelNames() {
+		for _, to := range ct.Transitions[from] {
+			parts = append(parts, from+channelTransitionSep+to)
+		}
+	}
+
+	return strings.Join(parts, channelSep)
+}
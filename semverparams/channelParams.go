@@ -0,0 +1,173 @@
+package semverparams
+
+import (
+	"fmt"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// AddChannelParam returns a function that will add a parameter for setting
+// the release Channel to the passed PSet. The channels are taken from the
+// passed SemverChecks, if it is nil or has no channels the
+// DefaultChannelTable is used. The value must be the name of one of the
+// channels.
+//
+// The SemVer is moved to the channel in a final check, so the SemVer must
+// also be given. If the SemVer is already in the channel its pre-release
+// IDs are just checked, otherwise they are replaced by those of the new
+// channel (for instance, v1.2.0-beta.3 moved to the "rc" channel becomes
+// v1.2.0-rc.1). A stable SemVer has its patch version incremented when it
+// is moved to a pre-release channel (see ChannelTable.Move) unless it has
+// been bumped. If the SemVer is to be bumped as well the bump parameters
+// should be added first so that the bump is applied before the move.
+func (svv *SemverVals) AddChannelParam(svCks *SemverChecks) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svv.Prefix != "" {
+			prefix = svv.Prefix + "-"
+		}
+
+		channelPN := svv.ChannelParam.resolve(prefix,
+			"channel", nil,
+			"specify the release channel (such as alpha, beta or rc)"+
+				" in which the "+semver.Name+" should be. The"+
+				" pre-release IDs of the "+semver.Name+" are checked"+
+				" against the channel or, if it is in an earlier"+
+				" channel, replaced with those of the new channel")
+
+		pp := svv.paramsFor(ps)
+		if pp.channel != nil {
+			return fmt.Errorf("the %q parameter has already been added"+
+				" to this PSet", channelPN.name)
+		}
+
+		if err := checkParamNames(ps, channelPN); err != nil {
+			return err
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		var ct *ChannelTable
+		if svCks != nil {
+			ct = &svCks.Channels
+		}
+
+		pp.channel = ps.Add(channelPN.name,
+			ChannelSetter{Value: &svv.Channel, Channels: ct},
+			channelPN.help,
+			param.AltNames(channelPN.altNames...),
+			param.PostAction(channelPN.deprecationAction(svv.errW())),
+			param.GroupName(groupName),
+			param.PostAction(pp.recordSource(srcValChannel)),
+		)
+
+		ps.AddFinalCheck(applyChannel(svv, pp, svCks))
+
+		return nil
+	}
+}
+
+// channelTable returns the channel table to use, the default table is used
+// if the SemverChecks is nil or has no channels
+func (svCks *SemverChecks) channelTable() ChannelTable {
+	if svCks == nil {
+		return DefaultChannelTable()
+	}
+
+	return svCks.Channels.orDefault()
+}
+
+// applyChannel moves the semver to the requested channel. If the semver has
+// been bumped it is not bumped again when it is moved from a stable channel.
+func applyChannel(
+	svv *SemverVals, pp *psetParams, svCks *SemverChecks,
+) param.FinalCheckFunc {
+	return func() error {
+		if svv.Channel == "" {
+			return nil
+		}
+
+		errPfx := ""
+
+		if svv.Desc != "" {
+			errPfx = svv.Desc + ": "
+		}
+
+		if !svv.SemVerHasBeenSet() {
+			return fmt.Errorf("%sthe %s cannot be put in the %q release channel"+
+				" as it has not been set",
+				errPfx, semver.Name, svv.Channel)
+		}
+
+		ct := svCks.channelTable()
+		if err := ct.Check(); err != nil {
+			return fmt.Errorf("%s%w", errPfx, err)
+		}
+
+		if err := ct.moveAfter(&svv.SemVer, svv.Channel, pp.bumpedFrom); err != nil {
+			return fmt.Errorf("%s%w", errPfx, err)
+		}
+
+		if svCks != nil {
+			if err := svCks.Check(&svv.SemVer); err != nil {
+				return fmt.Errorf("%s%w", errPfx, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// AddChannelTableParams will add parameters for setting the release
+// channels and the moves allowed between them to the group of the
+// SemverChecks. These can be given in the group config file so that all the
+// programs using the checks share the same channels. If the Channels are
+// empty they are first set to the DefaultChannelTable.
+func (svCks *SemverChecks) AddChannelTableParams() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svCks.Name != "" {
+			prefix = svCks.Name + "-"
+		}
+
+		var (
+			channelsParamName    = prefix + "channels"
+			transitionsParamName = prefix + "channel-transitions"
+		)
+
+		if len(svCks.Channels.Channels) == 0 {
+			svCks.Channels = DefaultChannelTable()
+		}
+
+		groupName := svCks.addGroup(ps)
+
+		ps.Add(channelsParamName,
+			ChannelsSetter{Value: &svCks.Channels},
+			"specify the release channels, and the pre-release IDs of"+
+				" the versions in each channel, for the "+
+				semver.Name+svCks.Desc,
+			param.GroupName(groupName),
+			param.SeeAlso(transitionsParamName),
+		)
+
+		ps.Add(transitionsParamName,
+			ChannelTransitionsSetter{Value: &svCks.Channels},
+			"specify the moves allowed between the release channels"+
+				" for the "+semver.Name+svCks.Desc+
+				". If this is not given a version may move to any"+
+				" later channel",
+			param.GroupName(groupName),
+			param.SeeAlso(channelsParamName),
+		)
+
+		ps.AddFinalCheck(func() error {
+			return svCks.Channels.Check()
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package semverparams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/psetter"
+)
+
+// ChannelSetter is a parameter setter which will set the name of a release
+// channel. The name must be that of one of the Channels. It satisfies the
+// param.Setter interface and so can be used when specifying a command line
+// argument using the param package.
+type ChannelSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *string
+	// Channels gives the allowed channels, if it is nil or has no channels
+	// the DefaultChannelTable is used
+	Channels *ChannelTable
+}
+
+// SetWithVal checks that the parameter value is the name of one of the
+// channels. The Value is only set if it is.
+func (cs ChannelSetter) SetWithVal(_ string, paramVal string) error {
+	ct := cs.Channels.orDefault()
+	if ct.find(paramVal) < 0 {
+		return fmt.Errorf("unknown release channel %q (it should be one of: %s)",
+			paramVal, strings.Join(ct.channelNames(), ", "))
+	}
+
+	*cs.Value = paramVal
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (cs ChannelSetter) AllowedValues() string {
+	return "the name of a release channel, one of: " +
+		strings.Join(cs.Channels.orDefault().channelNames(), ", ")
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (cs ChannelSetter) CurrentValue() string {
+	return *cs.Value
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (cs ChannelSetter) CheckSetter(name string) {
+	if cs.Value == nil {
+		panic(name + ": ChannelSetter Check failed: the Value to be set is nil")
+	}
+}
+
+// ChannelsSetter is a parameter setter which will set the release channels
+// of a ChannelTable. It satisfies the param.Setter interface and so can be
+// used when specifying a command line argument using the param package.
+type ChannelsSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *ChannelTable
+}
+
+// SetWithVal parses the parameter value as a list of release channels (see
+// ParseChannels). It returns an error if the list is invalid. Only if the
+// list is valid are the channels set.
+func (cs ChannelsSetter) SetWithVal(_ string, paramVal string) error {
+	channels, err := ParseChannels(paramVal)
+	if err != nil {
+		return err
+	}
+
+	cs.Value.Channels = channels
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (cs ChannelsSetter) AllowedValues() string {
+	return "a list of release channels, in order of increasing stability," +
+		" separated by '" + channelSep + "'. Each channel is a name" +
+		" optionally followed by '" + channelShapeSep + "' and the" +
+		" pre-release IDs of the versions in the channel, separated" +
+		" by '.', where '" + channelNumber + "' matches any number." +
+		" If the IDs are not given they are the name followed by a" +
+		" number and if they are empty the channel is for versions" +
+		" with no pre-release IDs." +
+		" For instance, 'alpha,beta,rc=rc." + channelNumber + ",stable='"
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (cs ChannelsSetter) CurrentValue() string {
+	return cs.Value.ChannelsString()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (cs ChannelsSetter) CheckSetter(name string) {
+	if cs.Value == nil {
+		panic(name + ": ChannelsSetter Check failed: the Value to be set is nil")
+	}
+}
+
+// ChannelTransitionsSetter is a parameter setter which will set the moves
+// allowed between the release channels of a ChannelTable. It satisfies the
+// param.Setter interface and so can be used when specifying a command line
+// argument using the param package.
+type ChannelTransitionsSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *ChannelTable
+}
+
+// SetWithVal parses the parameter value as a list of moves between release
+// channels (see ParseChannelTransitions). It returns an error if the list is
+// invalid. Only if the list is valid are the transitions set.
+func (cts ChannelTransitionsSetter) SetWithVal(_, paramVal string) error {
+	transitions, err := ParseChannelTransitions(paramVal)
+	if err != nil {
+		return err
+	}
+
+	cts.Value.Transitions = transitions
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (cts ChannelTransitionsSetter) AllowedValues() string {
+	return "a list of the moves allowed between release channels," +
+		" separated by '" + channelSep + "'. Each move is the name of" +
+		" a channel followed by '" + channelTransitionSep + "' and the" +
+		" name of the channel to which a version may move." +
+		" For instance, 'alpha" + channelTransitionSep + "beta," +
+		"beta" + channelTransitionSep + "rc," +
+		"rc" + channelTransitionSep + "stable'"
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (cts ChannelTransitionsSetter) CurrentValue() string {
+	return cts.Value.TransitionsString()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (cts ChannelTransitionsSetter) CheckSetter(name string) {
+	if cts.Value == nil {
+		panic(name +
+			": ChannelTransitionsSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,364 @@
+package semverparams_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParseChannels(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		s      string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("default channels"),
+			s:      "alpha,beta,rc=rc.#,stable=",
+			expStr: "alpha=alpha.#,beta=beta.#,rc=rc.#,stable=",
+		},
+		{
+			ID:     testhelper.MkID("custom shapes"),
+			s:      "nightly=dev.nightly.#, preview=pre, ga=",
+			expStr: "nightly=dev.nightly.#,preview=pre,ga=",
+		},
+		{
+			ID:     testhelper.MkID("bad name"),
+			ExpErr: testhelper.MkExpErr(`bad release channel name "a_b"`),
+			s:      "a_b",
+		},
+		{
+			ID:     testhelper.MkID("empty name"),
+			ExpErr: testhelper.MkExpErr(`bad release channel name ""`),
+			s:      "alpha,,beta",
+		},
+		{
+			ID:     testhelper.MkID("bad shape"),
+			ExpErr: testhelper.MkExpErr(`bad release channel "beta": `),
+			s:      "beta=beta.01",
+		},
+		{
+			ID:     testhelper.MkID("repeated name"),
+			ExpErr: testhelper.MkExpErr(`the release channel "beta" is repeated`),
+			s:      "beta,beta=b.#",
+		},
+		{
+			ID: testhelper.MkID("repeated shape"),
+			ExpErr: testhelper.MkExpErr(
+				`the release channels "ga" and "stable" have the same shape`),
+			s: "ga=,stable=",
+		},
+	}
+
+	for _, tc := range testCases {
+		channels, err := semverparams.ParseChannels(tc.s)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			ct := semverparams.ChannelTable{Channels: channels}
+			testhelper.DiffString(t, tc.IDStr(), "channels",
+				ct.ChannelsString(), tc.expStr)
+		}
+	}
+}
+
+func TestParseChannelTransitions(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		s      string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("good"),
+			s:      "alpha>beta,beta>rc,alpha>rc,rc>stable,rc>stable",
+			expStr: "alpha>beta,alpha>rc,beta>rc,rc>stable",
+		},
+		{
+			ID: testhelper.MkID("no separator"),
+			ExpErr: testhelper.MkExpErr(
+				`bad release channel transition "alpha-beta"`),
+			s: "alpha-beta",
+		},
+		{
+			ID: testhelper.MkID("missing target"),
+			ExpErr: testhelper.MkExpErr(
+				`bad release channel transition "rc>"`),
+			s: "alpha>beta,rc>",
+		},
+	}
+
+	for _, tc := range testCases {
+		transitions, err := semverparams.ParseChannelTransitions(tc.s)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			ct := semverparams.DefaultChannelTable()
+			ct.Transitions = transitions
+			testhelper.DiffString(t, tc.IDStr(), "transitions",
+				ct.TransitionsString(), tc.expStr)
+		}
+	}
+}
+
+func TestChannelMove(t *testing.T) {
+	restricted := semverparams.DefaultChannelTable()
+	restricted.Transitions = map[string][]string{
+		"alpha": {"beta"},
+		"beta":  {"rc"},
+		"rc":    {"stable"},
+	}
+
+	backwards := semverparams.ChannelTable{
+		Channels: []semverparams.Channel{
+			{Name: "rc", Shape: []string{"rc", "#"}},
+			{Name: "beta", Shape: []string{"beta", "#"}},
+		},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		ct    semverparams.ChannelTable
+		sv    string
+		to    string
+		expSV string
+	}{
+		{
+			ID:    testhelper.MkID("same channel"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0-beta.3",
+			to:    "beta",
+			expSV: "v1.2.0-beta.3",
+		},
+		{
+			ID:    testhelper.MkID("later channel"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0-alpha.3",
+			to:    "rc",
+			expSV: "v1.2.0-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("to stable"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0-rc.2+build.9",
+			to:    "stable",
+			expSV: "v1.2.0+build.9",
+		},
+		{
+			ID:    testhelper.MkID("from stable"),
+			ct:    restricted,
+			sv:    "v1.3.0",
+			to:    "beta",
+			expSV: "v1.3.1-beta.1",
+		},
+		{
+			ID:    testhelper.MkID("from stable to the first channel"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.3+build.4",
+			to:    "alpha",
+			expSV: "v1.2.4-alpha.1+build.4",
+		},
+		{
+			ID: testhelper.MkID("earlier channel"),
+			ExpErr: testhelper.MkExpErr(
+				`v1.2.0-rc.1 cannot move from the "rc" release channel` +
+					` to "alpha"`),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0-rc.1",
+			to:    "alpha",
+			expSV: "v1.2.0-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("allowed transition"),
+			ct:    restricted,
+			sv:    "v1.2.0-beta.7",
+			to:    "rc",
+			expSV: "v1.2.0-rc.1",
+		},
+		{
+			ID: testhelper.MkID("disallowed transition"),
+			ExpErr: testhelper.MkExpErr(
+				`cannot move from the "alpha" release channel to "rc"`),
+			ct:    restricted,
+			sv:    "v1.2.0-alpha.7",
+			to:    "rc",
+			expSV: "v1.2.0-alpha.7",
+		},
+		{
+			ID: testhelper.MkID("not a later version"),
+			ExpErr: testhelper.MkExpErr(
+				`moving v1.2.0-rc.1 from the "rc" release channel to "beta"` +
+					` would not give a later version`),
+			ct:    backwards,
+			sv:    "v1.2.0-rc.1",
+			to:    "beta",
+			expSV: "v1.2.0-rc.1",
+		},
+		{
+			ID: testhelper.MkID("unknown channel"),
+			ExpErr: testhelper.MkExpErr(
+				`unknown release channel "gamma"`,
+				"alpha, beta, rc, stable"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0",
+			to:    "gamma",
+			expSV: "v1.2.0",
+		},
+		{
+			ID: testhelper.MkID("not in a channel"),
+			ExpErr: testhelper.MkExpErr(
+				"v1.2.0-beta is not in any release channel"),
+			ct:    semverparams.DefaultChannelTable(),
+			sv:    "v1.2.0-beta",
+			to:    "rc",
+			expSV: "v1.2.0-beta",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semver.ParseSV(tc.sv)
+		if err != nil {
+			t.Fatal(tc.IDStr(), ": bad test semver: ", err)
+		}
+
+		err = tc.ct.Move(sv, tc.to)
+		testhelper.CheckExpErr(t, err, tc)
+		testhelper.DiffString(t, tc.IDStr(), "semver", sv.String(), tc.expSV)
+	}
+}
+
+func TestChannelParams(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		args    []string
+		expSV   string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID:    testhelper.MkID("no channel"),
+			args:  []string{"-semver", "v1.2.0-beta.2"},
+			expSV: "v1.2.0-beta.2",
+		},
+		{
+			ID:    testhelper.MkID("default channels"),
+			args:  []string{"-semver", "v1.2.0-beta.2", "-channel", "rc"},
+			expSV: "v1.2.0-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("from stable"),
+			args:  []string{"-semver", "v1.2.3", "-channel", "alpha"},
+			expSV: "v1.2.4-alpha.1",
+		},
+		{
+			ID: testhelper.MkID("from stable, bumped"),
+			args: []string{
+				"-semver", "v1.2.3", "-bump", "minor", "-channel", "beta",
+			},
+			expSV: "v1.3.0-beta.1",
+		},
+		{
+			ID: testhelper.MkID("custom channels"),
+			args: []string{
+				"-semver", "v1.2.0-dev.4",
+				"-channels", "dev,preview=pre.#,ga=",
+				"-channel", "preview",
+			},
+			expSV: "v1.2.0-pre.1",
+		},
+		{
+			ID: testhelper.MkID("disallowed transition"),
+			args: []string{
+				"-semver", "v1.2.0-alpha.2",
+				"-channel-transitions", "alpha>beta,beta>rc,rc>stable",
+				"-channel", "rc",
+			},
+			expSV: "v1.2.0-alpha.2",
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New("v1.2.0-alpha.2 cannot move from the" +
+						` "alpha" release channel to "rc"`),
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("unknown transition channel"),
+			args: []string{
+				"-semver", "v1.2.0-alpha.2",
+				"-channel-transitions", "alpha>gamma",
+			},
+			expSV: "v1.2.0-alpha.2",
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New("the release channel transitions refer to" +
+						` an unknown channel: "gamma"`),
+				},
+			},
+		},
+		{
+			ID:   testhelper.MkID("no semver"),
+			args: []string{"-channel", "rc"},
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New("the semantic version ID cannot be put" +
+						` in the "rc" release channel as it has not been set`),
+				},
+			},
+		},
+		{
+			ID:    testhelper.MkID("unknown channel"),
+			args:  []string{"-semver", "v1.2.0-beta.2", "-channel", "gamma"},
+			expSV: "v1.2.0-beta.2",
+			expErrs: errutil.ErrMap{
+				"channel": []error{
+					errors.New(`unknown release channel "gamma"` +
+						" (it should be one of: alpha, beta, rc, stable)" +
+						"\nAt: [command line]: Supplied Parameter:4:" +
+						` "-channel" "gamma"`),
+				},
+			},
+		},
+		{
+			ID:   testhelper.MkID("bad channels"),
+			args: []string{"-channels", "a_b"},
+			expErrs: errutil.ErrMap{
+				"channels": []error{
+					errors.New(`bad release channel name "a_b":` +
+						" it must be a non-empty string of letters," +
+						" digits or hyphens" +
+						"\nAt: [command line]: Supplied Parameter:2:" +
+						` "-channels" "a_b"`),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{}
+		svCks := semverparams.SemverChecks{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			svv.AddBumpParams(),
+			svv.AddSemverParam(&svCks),
+			svv.AddChannelParam(&svCks),
+			svCks.AddCheckParams(),
+			svCks.AddChannelTableParams(),
+		)
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		if tc.expSV != "" {
+			testhelper.DiffString(t, tc.IDStr(), "semver",
+				svv.SemVer.String(), tc.expSV)
+		}
+	}
+}
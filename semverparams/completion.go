@@ -0,0 +1,194 @@
+package semverparams
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/location.mod/location"
+	"github.com/nickwells/param.mod/v7/paction"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// Completer is implemented by parameter setters which can suggest values
+// for shell completion
+type Completer interface {
+	// Completions returns the suggested values which start with the partial
+	// value
+	Completions(partial string) []string
+}
+
+// completionRepoDir is the directory from which the git repository is
+// found when looking for the latest tag
+const completionRepoDir = "."
+
+// completionPreRelName is the name of the pre-release ID used when
+// suggesting the next pre-release IDs
+const completionPreRelName = "rc"
+
+// SVCandidates returns the suggested versions following the latest
+// version: the latest version itself and the next patch, minor and major
+// versions. If the latest version is a pre-release the release of that
+// version is suggested in place of the next patch version (see
+// Bump.Apply). Any build IDs are removed from the next versions.
+func SVCandidates(latest *semver.SV) []string {
+	candidates := []string{latest.String()}
+
+	for _, b := range []Bump{BumpPatch, BumpMinor, BumpMajor} {
+		next := &semver.SV{}
+		latest.CopyInto(next)
+		next.ClearBuildIDs()
+
+		b.Apply(next)
+
+		candidates = append(candidates, next.String())
+	}
+
+	return candidates
+}
+
+// NextPreRelIDs returns the suggested pre-release IDs following the latest
+// version. If the latest version has pre-release IDs of the form "rc.N"
+// then "rc.N+1" is suggested, otherwise "rc.1". The latest version may be
+// nil.
+func NextPreRelIDs(latest *semver.SV) string {
+	n := 1
+
+	if latest != nil {
+		ids := latest.PreRelIDs()
+		if len(ids) == 2 && ids[0] == completionPreRelName {
+			if i, err := strconv.Atoi(ids[1]); err == nil {
+				n = i + 1
+			}
+		}
+	}
+
+	return completionPreRelName + "." + strconv.Itoa(n)
+}
+
+// filterCompletions returns those candidates which start with the partial
+// value
+func filterCompletions(candidates []string, partial string) []string {
+	var matches []string
+
+	for _, c := range candidates {
+		if strings.HasPrefix(c, partial) {
+			matches = append(matches, c)
+		}
+	}
+
+	return matches
+}
+
+// latestTagSV returns the version of the latest tag with the prefix in the
+// git repository containing the current directory or nil if there is none
+func latestTagSV(tagPrefix string) *semver.SV {
+	tag, err := LatestRepoTag(completionRepoDir, tagPrefix)
+	if err != nil {
+		return nil
+	}
+
+	return &tag.SemVer
+}
+
+// Completions returns the suggested versions which start with the partial
+// value. The suggestions are the latest tag (with the TagPrefix) in the git
+// repository containing the current directory and the next patch, minor
+// and major versions. There are no suggestions if there are no tags.
+func (svs SVSetter) Completions(partial string) []string {
+	latest := latestTagSV(svs.TagPrefix)
+	if latest == nil {
+		return nil
+	}
+
+	partial = strings.TrimPrefix(partial, tagPrefixDir(svs.TagPrefix))
+
+	return filterCompletions(SVCandidates(latest), partial)
+}
+
+// IDListCompleter is a parameter setter for a list of IDs (as made by
+// IDListSetter) which will also suggest values for shell completion
+type IDListCompleter struct {
+	psetter.StrList[string]
+
+	// Candidates returns the suggested values, it may be nil
+	Candidates func() []string
+}
+
+// Completions returns the suggested values which start with the partial
+// value
+func (ilc IDListCompleter) Completions(partial string) []string {
+	if ilc.Candidates == nil {
+		return nil
+	}
+
+	return filterCompletions(ilc.Candidates(), partial)
+}
+
+// WriteCompletions writes the suggested values for the named parameter
+// which start with the partial value to the writer, one per line. It
+// returns an error if there is no such parameter or if its setter does not
+// implement the Completer interface.
+func WriteCompletions(
+	w io.Writer, ps *param.PSet, paramName, partial string,
+) error {
+	p, err := ps.GetParamByName(strings.TrimLeft(paramName, "-"))
+	if err != nil {
+		return err
+	}
+
+	c, ok := p.Setter().(Completer)
+	if !ok {
+		return fmt.Errorf("parameter %q has no completion suggestions",
+			p.Name())
+	}
+
+	for _, s := range c.Completions(partial) {
+		fmt.Fprintln(w, s)
+	}
+
+	return nil
+}
+
+// completeParamName is the name of the parameter used to request
+// completion suggestions
+const completeParamName = "complete-semver-param"
+
+// AddCompletionParam adds a parameter which can be used by shell
+// completion functions to get suggested values for the parameters whose
+// setters implement the Completer interface. The value is the name of the
+// parameter being completed and, optionally, an '=' followed by the partial
+// value. The suggestions are printed one per line and the program exits.
+// For instance, a bash completion function might call
+//
+//	prog -complete-semver-param "semver=${COMP_WORDS[COMP_CWORD]}"
+func AddCompletionParam(ps *param.PSet) error {
+	_ = AddSemverGroup(ps)
+
+	ps.Add(completeParamName, psetter.String[string]{Value: new(string)},
+		"print the suggested values for the named parameter"+
+			" (given as name=partial-value) and exit."+
+			" This is intended for use by shell completion functions",
+		param.GroupName(semverGroupName),
+		param.Attrs(param.CommandLineOnly|param.DontShowInStdUsage),
+		param.PostAction(
+			func(loc location.L, p *param.BaseParam, paramVals []string) error {
+				name, partial, _ := strings.Cut(
+					paramVals[len(paramVals)-1], "=")
+
+				var b strings.Builder
+
+				err := WriteCompletions(&b, ps, name, partial)
+				if err != nil {
+					return err
+				}
+
+				return paction.ReportAndExit(b.String())(loc, p, paramVals)
+			}),
+	)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package semverparams_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSVCandidates(t *testing.T) {
+	latest := semver.NewSVOrPanic(1, 2, 3, []string{"rc", "4"}, []string{"b"})
+
+	testhelper.DiffStringSlice(t, "SVCandidates", "candidates",
+		semverparams.SVCandidates(latest),
+		[]string{"v1.2.3-rc.4+b", "v1.2.3", "v1.3.0", "v2.0.0"})
+
+	latest = semver.NewSVOrPanic(1, 2, 3, nil, nil)
+
+	testhelper.DiffStringSlice(t, "SVCandidates", "candidates",
+		semverparams.SVCandidates(latest),
+		[]string{"v1.2.3", "v1.2.4", "v1.3.0", "v2.0.0"})
+}
+
+func TestNextPreRelIDs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		latest *semver.SV
+		expIDs string
+	}{
+		{
+			ID:     testhelper.MkID("no latest version"),
+			expIDs: "rc.1",
+		},
+		{
+			ID:     testhelper.MkID("release"),
+			latest: semver.NewSVOrPanic(1, 2, 3, nil, nil),
+			expIDs: "rc.1",
+		},
+		{
+			ID:     testhelper.MkID("release candidate"),
+			latest: semver.NewSVOrPanic(1, 2, 3, []string{"rc", "9"}, nil),
+			expIDs: "rc.10",
+		},
+		{
+			ID:     testhelper.MkID("other pre-release"),
+			latest: semver.NewSVOrPanic(1, 2, 3, []string{"beta", "2"}, nil),
+			expIDs: "rc.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		testhelper.DiffString(t, tc.IDStr(), "next pre-release IDs",
+			semverparams.NextPreRelIDs(tc.latest), tc.expIDs)
+	}
+}
+
+func TestWriteCompletions(t *testing.T) {
+	dir := t.TempDir()
+
+	runGit(t, dir, "init", "-q", "-b", "main")
+
+	err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("text\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runGit(t, dir, "add", "f.txt")
+	runGit(t, dir, "commit", "-q", "-m", "first")
+	runGit(t, dir, "tag", "svc/v1.4.0")
+	runGit(t, dir, "tag", "svc/v1.5.0-rc.2")
+	runGit(t, dir, "tag", "v3.0.0")
+
+	t.Chdir(dir)
+
+	svv := semverparams.SemverVals{TagPrefix: "svc"}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+		svv.AddIDParams(nil),
+		semverparams.AddCompletionParam,
+	)
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		paramName string
+		partial   string
+		expOut    []string
+	}{
+		{
+			ID:        testhelper.MkID("semver"),
+			paramName: "-semver",
+			expOut: []string{
+				"v1.5.0-rc.2", "v1.5.0", "v1.6.0", "v2.0.0",
+			},
+		},
+		{
+			ID:        testhelper.MkID("semver, partial value"),
+			paramName: "semver",
+			partial:   "svc/v1.",
+			expOut:    []string{"v1.5.0-rc.2", "v1.5.0", "v1.6.0"},
+		},
+		{
+			ID:        testhelper.MkID("semver, alternative name"),
+			paramName: "svn",
+			partial:   "v2",
+			expOut:    []string{"v2.0.0"},
+		},
+		{
+			ID:        testhelper.MkID("pre-release IDs"),
+			paramName: "pre-rel-IDs",
+			expOut:    []string{"rc.3"},
+		},
+		{
+			ID: testhelper.MkID("no completions"),
+			ExpErr: testhelper.MkExpErr(
+				`parameter "build-IDs" has no completion suggestions`),
+			paramName: "build-IDs",
+		},
+		{
+			ID:        testhelper.MkID("no such parameter"),
+			ExpErr:    testhelper.MkExpErr(`"nonesuch"`),
+			paramName: "nonesuch",
+		},
+	}
+
+	for _, tc := range testCases {
+		var b strings.Builder
+
+		err := semverparams.WriteCompletions(&b, ps, tc.paramName, tc.partial)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffStringSlice(t, tc.IDStr(), "completions",
+				strings.Fields(b.String()), tc.expOut)
+		}
+	}
+}
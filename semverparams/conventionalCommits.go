@@ -0,0 +1,186 @@
+package semverparams
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/internal/gitrepo"
+)
+
+// Bump records which part of a semantic version number should be
+// incremented
+type Bump int
+
+// These are the possible Bump values, in increasing order of significance
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+// String returns the name of the Bump
+func (b Bump) String() string {
+	switch b {
+	case BumpNone:
+		return "none"
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+
+	return fmt.Sprintf("unknown bump (%d)", int(b))
+}
+
+// Apply increments the part of the semantic version number given by the
+// Bump. Any pre-release IDs are cleared (see the semver Incr... methods),
+// nothing is changed for BumpNone. A pre-release version comes before the
+// version without its pre-release IDs so a patch bump releases that
+// version rather than incrementing it: v1.3.0-rc.1 gives v1.3.0.
+func (b Bump) Apply(sv *semver.SV) {
+	if b == BumpPatch && sv.HasPreRelIDs() {
+		sv.ClearPreRelIDs()
+
+		return
+	}
+
+	switch b {
+	case BumpPatch:
+		sv.IncrPatch()
+	case BumpMinor:
+		sv.IncrMinor()
+	case BumpMajor:
+		sv.IncrMajor()
+	}
+}
+
+// CommitMsg holds a commit message and the ID of the commit, if known
+type CommitMsg struct {
+	ID      string
+	Message string
+}
+
+// CommitBump records the Bump required by a commit and the reason for it
+type CommitBump struct {
+	CommitMsg
+	Bump   Bump
+	Reason string
+}
+
+// ccHeaderRE matches the header line of a Conventional Commits message. The
+// submatches are the type and the breaking change marker.
+var ccHeaderRE = regexp.MustCompile(`^([A-Za-z]+)(?:\([^()]*\))?(!?): \S`)
+
+// ccBreakingFooterRE matches a breaking change footer in a Conventional
+// Commits message
+var ccBreakingFooterRE = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// InferCommitBump returns the Bump required by the commit message according
+// to the Conventional Commits rules: a breaking change (shown by a '!'
+// before the ':' in the header or a 'BREAKING CHANGE:' footer) requires a
+// major bump, a 'feat' commit a minor bump and a 'fix' commit a patch
+// bump. Any other commit needs no bump.
+func InferCommitBump(cm CommitMsg) CommitBump {
+	cb := CommitBump{CommitMsg: cm}
+
+	header, _, _ := strings.Cut(strings.TrimSpace(cm.Message), "\n")
+
+	parts := ccHeaderRE.FindStringSubmatch(header)
+
+	switch {
+	case parts == nil:
+		cb.Reason = "not a Conventional Commits message"
+	case parts[2] == "!":
+		cb.Bump = BumpMajor
+		cb.Reason = "breaking change marked with '!'"
+	case ccBreakingFooterRE.MatchString(cm.Message):
+		cb.Bump = BumpMajor
+		cb.Reason = "breaking change footer"
+	case strings.EqualFold(parts[1], "feat"):
+		cb.Bump = BumpMinor
+		cb.Reason = "new feature"
+	case strings.EqualFold(parts[1], "fix"):
+		cb.Bump = BumpPatch
+		cb.Reason = "bug fix"
+	default:
+		cb.Reason = "commit type: " + parts[1]
+	}
+
+	return cb
+}
+
+// InferBump returns the largest Bump required by any of the commit
+// messages and the Bump inferred for each commit as a justification.
+func InferBump(msgs []CommitMsg) (Bump, []CommitBump) {
+	bump := BumpNone
+	commitBumps := make([]CommitBump, 0, len(msgs))
+
+	for _, cm := range msgs {
+		cb := InferCommitBump(cm)
+		bump = max(bump, cb.Bump)
+		commitBumps = append(commitBumps, cb)
+	}
+
+	return bump, commitBumps
+}
+
+// ReadCommitMsgs reads commit messages from the reader. If the content
+// holds any NUL characters it is split into messages at each NUL (as
+// produced by 'git log -z --format=%B'). Otherwise each non-blank line is
+// taken as a separate message (as produced by 'git log --format=%s').
+// Blank messages are ignored.
+func ReadCommitMsgs(r io.Reader) ([]CommitMsg, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sep := []byte{0}
+	if !bytes.Contains(content, sep) {
+		sep = []byte("\n")
+	}
+
+	var msgs []CommitMsg
+
+	for msg := range bytes.SplitSeq(content, sep) {
+		if s := strings.TrimSpace(string(msg)); s != "" {
+			msgs = append(msgs, CommitMsg{Message: s})
+		}
+	}
+
+	return msgs, nil
+}
+
+// GitCommitMsgs returns the messages of the commits in the git repository
+// containing the given directory which are reachable from the 'to'
+// revision but not from the 'from' revision (as for 'git log
+// from..to'). The commits are read directly from the repository's loose
+// objects and pack files. The messages are returned newest first.
+func GitCommitMsgs(repoDir, from, to string) ([]CommitMsg, error) {
+	r, err := gitrepo.Find(repoDir)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := r.CommitsBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]CommitMsg, 0, len(commits))
+	for _, c := range commits {
+		msgs = append(msgs, CommitMsg{
+			ID:      c.Hash.String(),
+			Message: strings.TrimSpace(c.Message),
+		})
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,328 @@
+package semverparams_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestInferCommitBump(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		msg     string
+		expBump semverparams.Bump
+	}{
+		{
+			ID:      testhelper.MkID("feature"),
+			msg:     "feat: add a thing",
+			expBump: semverparams.BumpMinor,
+		},
+		{
+			ID:      testhelper.MkID("feature with scope"),
+			msg:     "feat(parser): add a thing",
+			expBump: semverparams.BumpMinor,
+		},
+		{
+			ID:      testhelper.MkID("fix"),
+			msg:     "fix: mend a thing",
+			expBump: semverparams.BumpPatch,
+		},
+		{
+			ID:      testhelper.MkID("breaking, marked"),
+			msg:     "refactor(api)!: remove a thing",
+			expBump: semverparams.BumpMajor,
+		},
+		{
+			ID: testhelper.MkID("breaking, footer"),
+			msg: "fix: mend a thing\n\nsome detail\n\n" +
+				"BREAKING CHANGE: the thing has gone",
+			expBump: semverparams.BumpMajor,
+		},
+		{
+			ID:      testhelper.MkID("breaking, hyphenated footer"),
+			msg:     "feat: a thing\n\nBREAKING-CHANGE: gone",
+			expBump: semverparams.BumpMajor,
+		},
+		{
+			ID:      testhelper.MkID("other type"),
+			msg:     "docs: describe a thing",
+			expBump: semverparams.BumpNone,
+		},
+		{
+			ID:      testhelper.MkID("not conventional"),
+			msg:     "add a thing\n\nBREAKING CHANGE: ignored",
+			expBump: semverparams.BumpNone,
+		},
+		{
+			ID:      testhelper.MkID("no space after colon"),
+			msg:     "feat:a thing",
+			expBump: semverparams.BumpNone,
+		},
+	}
+
+	for _, tc := range testCases {
+		cb := semverparams.InferCommitBump(semverparams.CommitMsg{
+			Message: tc.msg,
+		})
+		testhelper.DiffString(t, tc.IDStr(), "bump",
+			cb.Bump.String(), tc.expBump.String())
+
+		if cb.Reason == "" {
+			t.Log(tc.IDStr())
+			t.Error("\t: no reason was given for the bump")
+		}
+	}
+}
+
+func TestInferBump(t *testing.T) {
+	bump, reasons := semverparams.InferBump([]semverparams.CommitMsg{
+		{ID: "a", Message: "fix: one"},
+		{ID: "b", Message: "feat: two"},
+		{ID: "c", Message: "chore: three"},
+	})
+
+	testhelper.DiffString(t, "InferBump", "bump",
+		bump.String(), semverparams.BumpMinor.String())
+
+	var got []string
+	for _, r := range reasons {
+		got = append(got, r.ID+":"+r.Bump.String())
+	}
+
+	testhelper.DiffStringSlice(t, "InferBump", "reasons",
+		got, []string{"a:patch", "b:minor", "c:none"})
+}
+
+func TestReadCommitMsgs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		content string
+		expMsgs []string
+	}{
+		{
+			ID:      testhelper.MkID("one per line"),
+			content: "feat: one\n\nfix: two\n",
+			expMsgs: []string{"feat: one", "fix: two"},
+		},
+		{
+			ID:      testhelper.MkID("NUL separated"),
+			content: "feat: one\n\nbody\n\x00fix: two\n\x00",
+			expMsgs: []string{"feat: one\n\nbody", "fix: two"},
+		},
+	}
+
+	for _, tc := range testCases {
+		msgs, err := semverparams.ReadCommitMsgs(strings.NewReader(tc.content))
+		if err != nil {
+			t.Fatal(tc.IDStr(), "unexpected error:", err)
+		}
+
+		var got []string
+		for _, m := range msgs {
+			got = append(got, m.Message)
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "messages", got, tc.expMsgs)
+	}
+}
+
+func TestBumpParams(t *testing.T) {
+	const commits = "testdata/commits/commits.txt"
+
+	testCases := []struct {
+		testhelper.ID
+		args       []string
+		expSV      string
+		expReasons int
+		expErrs    errutil.ErrMap
+	}{
+		{
+			ID:    testhelper.MkID("no bump"),
+			args:  []string{"-semver", "v1.2.3-rc.1"},
+			expSV: "v1.2.3-rc.1",
+		},
+		{
+			ID:    testhelper.MkID("explicit bump"),
+			args:  []string{"-semver", "v1.2.3", "-bump", "minor"},
+			expSV: "v1.3.0",
+		},
+		{
+			ID:    testhelper.MkID("patch bump of a pre-release"),
+			args:  []string{"-semver", "v1.3.0-rc.1", "-bump", "patch"},
+			expSV: "v1.3.0",
+		},
+		{
+			ID:    testhelper.MkID("minor bump of a pre-release"),
+			args:  []string{"-semver", "v1.3.0-rc.1", "-bump", "minor"},
+			expSV: "v1.4.0",
+		},
+		{
+			ID: testhelper.MkID("auto bump from file"),
+			args: []string{
+				"-semver", "v1.2.3",
+				"-bump", "auto", "-bump-commits", commits,
+			},
+			expSV:      "v1.3.0",
+			expReasons: 3,
+		},
+		{
+			ID:   testhelper.MkID("no semver"),
+			args: []string{"-bump", "major"},
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New("the semantic version ID cannot be bumped" +
+						" as it has not been set"),
+				},
+			},
+		},
+		{
+			ID:    testhelper.MkID("auto bump, no commits"),
+			args:  []string{"-semver", "v1.2.3", "-bump", "auto"},
+			expSV: "v1.2.3",
+			expErrs: errutil.ErrMap{
+				"Final Checks": []error{
+					errors.New("there is no source of commit messages" +
+						" for '-bump auto'"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			semverparams.AddSemverGroup,
+			svv.AddSemverParam(nil),
+			svv.AddBumpParams(),
+		)
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		if tc.expSV != "" {
+			testhelper.DiffString(t, tc.IDStr(), "semver",
+				svv.SemVer.String(), tc.expSV)
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "reasons",
+			len(svv.BumpReasons), tc.expReasons)
+	}
+}
+
+func TestBumpParamNames(t *testing.T) {
+	svv := semverparams.SemverVals{
+		Prefix: "rel",
+		BumpParam: semverparams.ParamSpec{
+			Name:     "incr",
+			AltNames: []string{"inc"},
+		},
+		BumpCommitsParam: semverparams.ParamSpec{Name: "incr-commits"},
+		BumpGitRevsParam: semverparams.ParamSpec{Name: "incr-git-revs"},
+	}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		svv.AddSemverParam(nil),
+		svv.AddBumpParams(),
+	)
+
+	for _, name := range []string{
+		"rel-incr", "rel-inc", "rel-incr-commits", "rel-incr-git-revs",
+	} {
+		if _, err := ps.GetParamByName(name); err != nil {
+			t.Errorf("cannot find the %q parameter: %v", name, err)
+		}
+	}
+
+	ps.Parse([]string{"-rel-semver", "v1.2.3", "-rel-inc", "auto"})
+
+	expErrs := errutil.ErrMap{
+		"Final Checks": []error{
+			errors.New("there is no source of commit messages" +
+				" for '-rel-incr auto'"),
+		},
+	}
+	if err := expErrs.Matches(ps.Errors()); err != nil {
+		t.Error(err)
+	}
+}
+
+// runGit runs the git command in the given directory. It skips the test if
+// git is not available and reports a fatal error if the command fails.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("the git program is not available")
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+		"GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func TestGitCommitMsgs(t *testing.T) {
+	dir := t.TempDir()
+
+	git := func(args ...string) {
+		t.Helper()
+		runGit(t, dir, args...)
+	}
+
+	git("init", "-q", "-b", "main")
+
+	for _, msg := range []string{
+		"feat: first",
+		"fix: second",
+		"feat!: third\n\nremove the old interface",
+	} {
+		err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte(msg), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		git("add", "f.txt")
+		git("commit", "-q", "-m", msg)
+
+		if msg == "feat: first" {
+			git("tag", "v1.0.0")
+		}
+	}
+
+	msgs, err := semverparams.GitCommitMsgs(dir, "v1.0.0", "HEAD")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	bump, reasons := semverparams.InferBump(msgs)
+
+	testhelper.DiffString(t, "GitCommitMsgs", "bump",
+		bump.String(), semverparams.BumpMajor.String())
+	testhelper.DiffInt(t, "GitCommitMsgs", "commits", len(reasons), 2)
+
+	for _, r := range reasons {
+		if len(r.ID) != 40 {
+			t.Errorf("GitCommitMsgs: bad commit ID: %q", r.ID)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package semverparams_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/semverparams.mod/v6/semverparamstest"
+)
+
+// fuzzSeeds is the number of generated values added to each seed corpus
+const fuzzSeeds = 50
+
+func FuzzSVSetter(f *testing.F) {
+	g := semverparamstest.NewGenerator(1, semverparamstest.DefaultGenConfig)
+
+	for range fuzzSeeds {
+		f.Add(g.SV().String())
+		f.Add(g.InvalidSVString())
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		var sv semver.SV
+
+		svs := semverparams.SVSetter{Value: &sv}
+		if err := svs.SetWithVal("semver", s); err != nil {
+			return
+		}
+
+		var rt semver.SV
+
+		svs.Value = &rt
+		if err := svs.SetWithVal("semver", sv.String()); err != nil {
+			t.Fatalf("%q was accepted but its String form %q was not: %v",
+				s, sv.String(), err)
+		}
+
+		if !semver.Equals(&sv, &rt) || rt.String() != sv.String() {
+			t.Fatalf("%q: the round trip gave %q, expected %q",
+				s, rt.String(), sv.String())
+		}
+	})
+}
+
+func FuzzIDListSetter(f *testing.F) {
+	g := semverparamstest.NewGenerator(1, semverparamstest.DefaultGenConfig)
+
+	for range fuzzSeeds {
+		f.Add(strings.Join(g.PreRelIDs(), "."), true)
+		f.Add(g.InvalidPreRelIDs(), true)
+		f.Add(strings.Join(g.BuildIDs(), "."), false)
+		f.Add(g.InvalidBuildIDs(), false)
+	}
+
+	f.Fuzz(func(t *testing.T, s string, preRel bool) {
+		var idChk check.ValCk[string] = semver.CheckBuildID
+		if preRel {
+			idChk = semver.CheckPreRelID
+		}
+
+		var ids []string
+
+		setter := semverparams.IDListSetter(&ids, idChk)
+		if err := setter.SetWithVal("ids", s); err != nil {
+			return
+		}
+
+		for _, id := range ids {
+			if err := idChk(id); err != nil {
+				t.Fatalf("%q was accepted but has a bad ID: %v", s, err)
+			}
+		}
+
+		var rt []string
+
+		cv := setter.CurrentValue()
+
+		setter.Value = &rt
+		if err := setter.SetWithVal("ids", cv); err != nil {
+			t.Fatalf("%q was accepted but its current value %q was not: %v",
+				s, cv, err)
+		}
+
+		if !slices.Equal(ids, rt) {
+			t.Fatalf("%q: the round trip gave %q, expected %q", s, rt, ids)
+		}
+	})
+}
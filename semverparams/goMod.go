@@ -0,0 +1,278 @@
+package semverparams
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// GoModDep records a module path and version given in a go.mod file
+// together with the line on which it was given
+type GoModDep struct {
+	Path string
+	// Version is the module version. It will not have been set if the
+	// directive has no version, as for a replacement by a local directory.
+	Version semver.SV
+	// Line is the line number (starting at 1) of the directive
+	Line int
+	// Indirect is set if the requirement is marked with an '// indirect'
+	// comment
+	Indirect bool
+}
+
+// String returns the module path and version (if any) separated by a space
+func (gmd GoModDep) String() string {
+	if !gmd.Version.HasBeenSet() {
+		return gmd.Path
+	}
+
+	return gmd.Path + " " + gmd.Version.String()
+}
+
+// GoModReplace records a replace directive in a go.mod file
+type GoModReplace struct {
+	// Old is the module being replaced. Its Version will only have been
+	// set if just that version of the module is replaced.
+	Old GoModDep
+	// New is the replacement module
+	New GoModDep
+}
+
+// GoMod holds the module dependencies read from a go.mod file
+type GoMod struct {
+	Filename string
+	Module   string
+
+	// Require maps the path of each required module to the requirement
+	Require map[string]GoModDep
+	// Replace maps the path of each replaced module to its replacements
+	Replace map[string][]GoModReplace
+	// Exclude maps the path of each excluded module to the excluded
+	// versions
+	Exclude map[string][]GoModDep
+}
+
+// pseudoVersionRE matches the final pre-release ID of a Go pseudo-version
+var pseudoVersionRE = regexp.MustCompile(`^[0-9]{14}-[0-9a-f]{12}$`)
+
+// IsPseudoVersion returns true if the version is a Go pseudo-version, as
+// used to refer to a module revision which has no version tag.
+func IsPseudoVersion(sv *semver.SV) bool {
+	ids := sv.PreRelIDs()
+	if len(ids) == 0 {
+		return false
+	}
+
+	return pseudoVersionRE.MatchString(ids[len(ids)-1])
+}
+
+// ReadGoMod reads the named go.mod file and returns the dependencies it
+// gives.
+func ReadGoMod(filename string) (*GoMod, error) {
+	content, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseGoMod(filename, content)
+}
+
+// goModTokens splits the line into tokens, unquoting any quoted tokens. Any
+// comment is removed and returned separately.
+func goModTokens(line string) ([]string, string, error) {
+	line, comment, _ := strings.Cut(line, "//")
+
+	var tokens []string
+
+	for _, f := range strings.Fields(line) {
+		if strings.HasPrefix(f, `"`) || strings.HasPrefix(f, "`") {
+			u, err := strconv.Unquote(f)
+			if err != nil {
+				return nil, "", fmt.Errorf("bad quoted string: %s", f)
+			}
+
+			f = u
+		}
+
+		tokens = append(tokens, f)
+	}
+
+	return tokens, strings.TrimSpace(comment), nil
+}
+
+// goModVersion parses the version in a go.mod file directive
+func goModVersion(v string) (semver.SV, error) {
+	sv := semver.SV{}
+
+	if !strings.HasPrefix(v, "v") {
+		return sv, fmt.Errorf("bad module version %q: no leading 'v'", v)
+	}
+
+	err := setFromVersionText(&sv, v)
+
+	return sv, err
+}
+
+// ParseGoMod finds the module dependencies in the content of a go.mod
+// file. The filename is used to give context in any error. Any error will
+// give the filename and the line number where the problem was found.
+func ParseGoMod(filename string, content []byte) (*GoMod, error) {
+	gm := &GoMod{
+		Filename: filename,
+		Require:  map[string]GoModDep{},
+		Replace:  map[string][]GoModReplace{},
+		Exclude:  map[string][]GoModDep{},
+	}
+
+	block := ""
+
+	for i, l := range manifestLines(content) {
+		lineNum := i + 1
+
+		tokens, comment, err := goModTokens(l.text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filename, lineNum, err)
+		}
+
+		if len(tokens) == 0 {
+			continue
+		}
+
+		verb := block
+
+		switch {
+		case block != "" && tokens[0] == ")":
+			block = ""
+			continue
+		case block == "" && len(tokens) == 2 && tokens[1] == "(":
+			block = tokens[0]
+			continue
+		case block == "":
+			verb, tokens = tokens[0], tokens[1:]
+		}
+
+		err = gm.addDirective(verb, tokens, comment, lineNum)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %s: %w",
+				filename, lineNum, verb, err)
+		}
+	}
+
+	return gm, nil
+}
+
+// addDirective records the details of the directive
+func (gm *GoMod) addDirective(
+	verb string, args []string, comment string, lineNum int,
+) error {
+	switch verb {
+	case "module":
+		if len(args) != 1 {
+			return fmt.Errorf("expected 1 argument, found %d", len(args))
+		}
+
+		gm.Module = args[0]
+	case "require", "exclude":
+		const reqArgCount = 2
+		if len(args) != reqArgCount {
+			return fmt.Errorf("expected a module path and version, found %q",
+				args)
+		}
+
+		sv, err := goModVersion(args[1])
+		if err != nil {
+			return err
+		}
+
+		dep := GoModDep{
+			Path:     args[0],
+			Version:  sv,
+			Line:     lineNum,
+			Indirect: comment == "indirect",
+		}
+
+		if verb == "require" {
+			gm.Require[dep.Path] = dep
+		} else {
+			gm.Exclude[dep.Path] = append(gm.Exclude[dep.Path], dep)
+		}
+	case "replace":
+		return gm.addReplace(args, lineNum)
+	}
+
+	return nil
+}
+
+// addReplace records the details of a replace directive
+func (gm *GoMod) addReplace(args []string, lineNum int) error {
+	arrow := -1
+
+	for i, a := range args {
+		if a == "=>" {
+			arrow = i
+		}
+	}
+
+	oldArgs := arrow
+	newArgs := len(args) - arrow - 1
+
+	if arrow < 0 || oldArgs < 1 || oldArgs > 2 || newArgs < 1 || newArgs > 2 {
+		return fmt.Errorf("expected 'module [version] => module [version]',"+
+			" found %q", args)
+	}
+
+	rep := GoModReplace{
+		Old: GoModDep{Path: args[0], Line: lineNum},
+		New: GoModDep{Path: args[arrow+1], Line: lineNum},
+	}
+
+	if oldArgs == 2 {
+		sv, err := goModVersion(args[1])
+		if err != nil {
+			return err
+		}
+
+		rep.Old.Version = sv
+	}
+
+	if newArgs == 2 {
+		sv, err := goModVersion(args[arrow+2])
+		if err != nil {
+			return err
+		}
+
+		rep.New.Version = sv
+	}
+
+	gm.Replace[rep.Old.Path] = append(gm.Replace[rep.Old.Path], rep)
+
+	return nil
+}
+
+// Replacement returns the module which replaces the given dependency and
+// true, or false if it is not replaced. As for the go command, a
+// replacement of the given version takes precedence over one of all
+// versions of the module.
+func (gm *GoMod) Replacement(dep GoModDep) (GoModDep, bool) {
+	var (
+		newDep GoModDep
+		found  bool
+	)
+
+	for _, rep := range gm.Replace[dep.Path] {
+		switch {
+		case !rep.Old.Version.HasBeenSet():
+			if !found {
+				newDep, found = rep.New, true
+			}
+		case semver.Equals(&rep.Old.Version, &dep.Version):
+			return rep.New, true
+		}
+	}
+
+	return newDep, found
+}
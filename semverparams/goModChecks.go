@@ -0,0 +1,226 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+)
+
+// DepRangeSetter is a parameter setter which will add an entry to a map of
+// module paths to the Range of versions allowed for that module. The
+// parameter value is a module path and a range expression separated by
+// '='. It satisfies the param.Setter interface and so can be used when
+// specifying a command line argument using the param package.
+type DepRangeSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *map[string]*Range
+}
+
+// SetWithVal splits the parameter value into the module path and range
+// expression and parses the range. It returns an error if the value cannot
+// be split or the range expression is invalid. Only if there is no error
+// is the map entry set.
+func (drs DepRangeSetter) SetWithVal(_ string, paramVal string) error {
+	path, rangeExpr, ok := strings.Cut(paramVal, "=")
+	path = strings.TrimSpace(path)
+
+	if !ok || path == "" {
+		return fmt.Errorf("bad dependency range %q:"+
+			" it should be a module path and a range separated by '='",
+			paramVal)
+	}
+
+	r, err := ParseRange(rangeExpr)
+	if err != nil {
+		return err
+	}
+
+	if *drs.Value == nil {
+		*drs.Value = map[string]*Range{}
+	}
+
+	(*drs.Value)[path] = r
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (drs DepRangeSetter) AllowedValues() string {
+	return "a module path followed by '=' and then " + rangeAllowedValues +
+		" For instance, 'github.com/nickwells/param.mod/v7=>=v7.2'"
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (drs DepRangeSetter) CurrentValue() string {
+	var s strings.Builder
+
+	sep := ""
+
+	for _, path := range slices.Sorted(maps.Keys(*drs.Value)) {
+		s.WriteString(sep + path + "=" + (*drs.Value)[path].String())
+		sep = ", "
+	}
+
+	return s.String()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (drs DepRangeSetter) CheckSetter(name string) {
+	if drs.Value == nil {
+		panic(name +
+			": DepRangeSetter Check failed: the Value to be set is nil")
+	}
+}
+
+// GoModChecks holds the checks to be applied to the dependencies given in a
+// go.mod file. If you want to have multiple GoModChecks each will need its
+// own distinct Name.
+type GoModChecks struct {
+	// Name, if not empty, will be applied as a prefix to the parameter
+	// names, separated from the rest of the parameter name with '-'. The
+	// Name is also used as a suffix to the group name.
+	//
+	// Note that it must be suitable to be part of a parameter name (it must
+	// start with a letter and be followed with letters, digits or dashes
+	// '-')
+	Name string
+
+	// GoModFile is the name of the go.mod file to be checked. If it is
+	// empty then "go.mod" is used.
+	GoModFile string
+
+	// DepRanges maps module paths to the range of versions allowed for
+	// that module. Any required module not in the map is not checked
+	// against a range. Each module in the map must be required.
+	DepRanges map[string]*Range
+
+	// NoPseudoVersions, if set, means that no required module may be given
+	// with a pseudo-version.
+	NoPseudoVersions bool
+}
+
+const goModChecksGroupName = "semver-go-mod-checks"
+
+// dfltGoModFile is the name of the go.mod file to check if none is given
+const dfltGoModFile = "go.mod"
+
+// Check checks the required modules in the GoMod against the checks and
+// returns a slice of errors, one for each violation. Each error gives the
+// go.mod file and the line of the failing requirement. If a required
+// module is replaced the replacement is checked instead and the error
+// gives the line of the replace directive; a module replaced by a local
+// directory has no version and so cannot be in any range. A range given
+// for a module which is not required is also reported, as it is probably a
+// mistake in the module path.
+func (gmc GoModChecks) Check(gm *GoMod) []error {
+	var errs []error
+
+	for _, path := range slices.Sorted(maps.Keys(gm.Require)) {
+		dep := gm.Require[path]
+		name := path
+
+		if rep, ok := gm.Replacement(dep); ok {
+			dep = rep
+			name = path + " => " + rep.Path
+		}
+
+		r, hasRange := gmc.DepRanges[path]
+
+		switch {
+		case !dep.Version.HasBeenSet():
+			if hasRange {
+				errs = append(errs, fmt.Errorf(
+					"%s:%d: %s: a local directory is not in range %q",
+					gm.Filename, dep.Line, name, r))
+			}
+
+			continue
+		case hasRange && !r.Contains(&dep.Version):
+			errs = append(errs, fmt.Errorf("%s:%d: %s: %s is not in range %q",
+				gm.Filename, dep.Line, name, dep.Version, r))
+		}
+
+		if gmc.NoPseudoVersions && IsPseudoVersion(&dep.Version) {
+			errs = append(errs, fmt.Errorf("%s:%d: %s: %s is a pseudo-version",
+				gm.Filename, dep.Line, name, dep.Version))
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(gmc.DepRanges)) {
+		if _, ok := gm.Require[path]; !ok {
+			errs = append(errs, fmt.Errorf(
+				"%s: %s: a range is given but the module is not required",
+				gm.Filename, path))
+		}
+	}
+
+	return errs
+}
+
+// AddGoModCheckParams will add parameters for setting the checks to be
+// applied to the dependencies in a go.mod file. It also adds a final check
+// which reads the go.mod file and applies the checks if any have been given.
+func (gmc *GoModChecks) AddGoModCheckParams() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		groupName := goModChecksGroupName
+
+		if gmc.Name != "" {
+			prefix = gmc.Name + "-"
+			groupName += "-" + gmc.Name
+		}
+
+		if gmc.GoModFile == "" {
+			gmc.GoModFile = dfltGoModFile
+		}
+
+		ps.AddGroup(groupName,
+			"common parameters for specifying checks on"+
+				" the versions of the modules required by a go.mod file")
+
+		ps.Add(prefix+"go-mod-file",
+			psetter.Pathname{Value: &gmc.GoModFile},
+			"give the name of the go.mod file to be checked",
+			param.GroupName(groupName),
+		)
+
+		ps.Add(prefix+"dep-range",
+			DepRangeSetter{Value: &gmc.DepRanges},
+			"give the range of versions allowed for a required module."+
+				" This may be given multiple times to check"+
+				" several modules",
+			param.AltNames(prefix+"dep-rng"),
+			param.GroupName(groupName),
+		)
+
+		ps.Add(prefix+"dep-no-pseudo",
+			psetter.Bool{Value: &gmc.NoPseudoVersions},
+			"forbid the use of pseudo-versions for any required module",
+			param.GroupName(groupName),
+		)
+
+		ps.AddFinalCheck(gmc.finalCheck)
+
+		return nil
+	}
+}
+
+// finalCheck reads the go.mod file and applies the checks, if any
+func (gmc *GoModChecks) finalCheck() error {
+	if len(gmc.DepRanges) == 0 && !gmc.NoPseudoVersions {
+		return nil
+	}
+
+	gm, err := ReadGoMod(gmc.GoModFile)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(gmc.Check(gm)...)
+}
@@ -0,0 +1,233 @@
+package semverparams_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const testGoMod = `module example.com/m
+
+go 1.26.0
+
+require github.com/a/b/v2 v2.6.1
+
+require (
+	github.com/c/d v0.0.0-20260101120000-0123456789ab // indirect
+	"github.com/e/f" v1.9.0+incompatible
+)
+
+replace (
+	github.com/e/f => ../f
+	github.com/a/b/v2 v2.6.1 => github.com/x/b/v2 v2.6.2
+)
+
+exclude github.com/a/b/v2 v2.6.0
+exclude github.com/a/b/v2 v2.5.0
+`
+
+func TestParseGoMod(t *testing.T) {
+	gm, err := semverparams.ParseGoMod("go.mod", []byte(testGoMod))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffString(t, "module", "", gm.Module, "example.com/m")
+	testhelper.DiffInt(t, "require", "count", len(gm.Require), 3)
+
+	for _, exp := range []struct {
+		path     string
+		version  string
+		line     int
+		indirect bool
+	}{
+		{"github.com/a/b/v2", "v2.6.1", 5, false},
+		{"github.com/c/d", "v0.0.0-20260101120000-0123456789ab", 8, true},
+		{"github.com/e/f", "v1.9.0+incompatible", 9, false},
+	} {
+		dep := gm.Require[exp.path]
+		testhelper.DiffString(t, exp.path, "version",
+			dep.Version.String(), exp.version)
+		testhelper.DiffInt(t, exp.path, "line", dep.Line, exp.line)
+		testhelper.DiffBool(t, exp.path, "indirect", dep.Indirect, exp.indirect)
+	}
+
+	for _, exp := range []struct {
+		name   string
+		dep    semverparams.GoModDep
+		expRep string
+	}{
+		{"local", gm.Require["github.com/e/f"], "../f"},
+		{
+			"versioned",
+			gm.Require["github.com/a/b/v2"],
+			"github.com/x/b/v2 v2.6.2",
+		},
+		{"not replaced", gm.Require["github.com/c/d"], ""},
+		{
+			"other version",
+			semverparams.GoModDep{
+				Path:    "github.com/a/b/v2",
+				Version: gm.Exclude["github.com/a/b/v2"][0].Version,
+			},
+			"",
+		},
+	} {
+		rep, ok := gm.Replacement(exp.dep)
+		if !testhelper.DiffBool(t, "Replacement", exp.name,
+			ok, exp.expRep != "") && ok {
+			testhelper.DiffString(t, "Replacement", exp.name,
+				rep.String(), exp.expRep)
+		}
+	}
+
+	testhelper.DiffInt(t, "exclude", "count",
+		len(gm.Exclude["github.com/a/b/v2"]), 2)
+
+	for path, exp := range map[string]bool{
+		"github.com/c/d":    true,
+		"github.com/a/b/v2": false,
+	} {
+		sv := gm.Require[path].Version
+		testhelper.DiffBool(t, "IsPseudoVersion", path,
+			semverparams.IsPseudoVersion(&sv), exp)
+	}
+
+	badGoModTests := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		content string
+	}{
+		{
+			ID: testhelper.MkID("bad version"),
+			ExpErr: testhelper.MkExpErr(
+				"go.mod:2: require: bad module version \"1.2.3\""),
+			content: "module x\nrequire a.b/c 1.2.3\n",
+		},
+		{
+			ID: testhelper.MkID("missing version"),
+			ExpErr: testhelper.MkExpErr(
+				"go.mod:3: require: expected a module path and version"),
+			content: "module x\nrequire (\n\ta.b/c\n)\n",
+		},
+		{
+			ID: testhelper.MkID("bad replace"),
+			ExpErr: testhelper.MkExpErr(
+				"go.mod:1: replace: expected 'module [version] =>"),
+			content: "replace a.b/c v1.0.0\n",
+		},
+	}
+
+	for _, tc := range badGoModTests {
+		_, err := semverparams.ParseGoMod("go.mod", []byte(tc.content))
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestGoModChecks(t *testing.T) {
+	gm, err := semverparams.ParseGoMod("go.mod", []byte(testGoMod))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	r, err := semverparams.ParseRange(">=v2.7")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	gmc := semverparams.GoModChecks{
+		DepRanges: map[string]*semverparams.Range{
+			"github.com/a/b/v2": r,
+			"github.com/a/bb":   r,
+		},
+		NoPseudoVersions: true,
+	}
+
+	errs := gmc.Check(gm)
+	testhelper.DiffStringSlice(t, "Check", "errors",
+		testhelper.ErrSliceToStrSlice(errs),
+		[]string{
+			"go.mod:14: github.com/a/b/v2 => github.com/x/b/v2:" +
+				` v2.6.2 is not in range ">=v2.7"`,
+			"go.mod:8: github.com/c/d:" +
+				" v0.0.0-20260101120000-0123456789ab is a pseudo-version",
+			"go.mod: github.com/a/bb:" +
+				" a range is given but the module is not required",
+		})
+
+	const replacedGoMod = `module example.com/m
+
+require (
+	github.com/a/b v1.2.0
+	github.com/c/d v1.0.0
+	github.com/e/f v1.0.0
+)
+
+replace github.com/a/b => github.com/a/b v1.3.0
+replace github.com/c/d v1.0.0 => github.com/x/d v0.0.0-20260101120000-0123456789ab
+replace github.com/e/f => ../f
+`
+
+	gm, err = semverparams.ParseGoMod("go.mod", []byte(replacedGoMod))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	gmc = semverparams.GoModChecks{DepRanges: map[string]*semverparams.Range{}}
+
+	for path, rangeExpr := range map[string]string{
+		"github.com/a/b": ">=v1.3",
+		"github.com/c/d": ">=v1",
+		"github.com/e/f": ">=v1",
+	} {
+		r, err := semverparams.ParseRange(rangeExpr)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		gmc.DepRanges[path] = r
+	}
+
+	gmc.NoPseudoVersions = true
+
+	errs = gmc.Check(gm)
+	testhelper.DiffStringSlice(t, "Check replaced", "errors",
+		testhelper.ErrSliceToStrSlice(errs),
+		[]string{
+			"go.mod:10: github.com/c/d => github.com/x/d:" +
+				` v0.0.0-20260101120000-0123456789ab is not in range ">=v1"`,
+			"go.mod:10: github.com/c/d => github.com/x/d:" +
+				" v0.0.0-20260101120000-0123456789ab is a pseudo-version",
+			"go.mod:11: github.com/e/f => ../f:" +
+				` a local directory is not in range ">=v1"`,
+		})
+
+	parseErrs := errutil.ErrMap{}
+	parseErrs.AddError("Final Checks", errors.Join(
+		errors.New("testdata/gomod/go.mod.txt:6: github.com/a/b/v2:"+
+			` v2.5.0 is not in range ">=v2.6 <v3"`),
+		errors.New("testdata/gomod/go.mod.txt:7: github.com/c/d:"+
+			" v0.0.0-20260101120000-0123456789ab is a pseudo-version")))
+	parseErrs.AddError("dep-range",
+		errors.New(`bad dependency range "x":`+
+			" it should be a module path and a range separated by '='\n"+
+			`At: [command line]: Supplied Parameter:6: "-dep-range=x"`))
+
+	gmc = semverparams.GoModChecks{}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(gmc.AddGoModCheckParams())
+	ps.Parse([]string{
+		"-go-mod-file", "testdata/gomod/go.mod.txt",
+		"-dep-range", "github.com/a/b/v2=>=v2.6 <v3",
+		"-dep-no-pseudo",
+		"-dep-range=x",
+	})
+
+	if err := parseErrs.Matches(ps.Errors()); err != nil {
+		t.Log("unexpected parse errors:", ps.Errors())
+		t.Error(err)
+	}
+}
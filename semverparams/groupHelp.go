@@ -0,0 +1,108 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/phelp"
+)
+
+// groupHelper is a param.Helper which shows the standard help but never
+// exits and doesn't report errors. It allows the help to be rendered on
+// demand rather than in response to the program parameters.
+type groupHelper struct {
+	*phelp.StdHelp
+}
+
+// ProcessArgs does nothing, the help is shown by calling Help directly
+func (groupHelper) ProcessArgs(*param.PSet) {}
+
+// ErrorHandler does nothing, any errors are ignored
+func (groupHelper) ErrorHandler(*param.PSet) {}
+
+// isSemverGroup returns true if the group name is that of one of the
+// groups to which this package adds parameters
+func isSemverGroup(name string) bool {
+	return name == semverGroupName ||
+		name == semverChecksGroupName ||
+		strings.HasPrefix(name, semverChecksGroupName+"-")
+}
+
+// WriteGroupHelp writes the help text for the semver and semver-checks
+// parameter groups to the writer, wrapped to the given width (a width of 0
+// gives the standard width). The parameters are added by the PSetOptFuncs
+// in the same way as when constructing a PSet, for instance:
+//
+//	err := semverparams.WriteGroupHelp(w, 80,
+//		[]string{svv.GroupName},
+//		svv.AddSemverParam(&svCks),
+//		svCks.AddCheckParams())
+//
+// The otherGroups give the names of any other groups to be shown, such as
+// the GroupName of a SemverVals whose parameters have been put in a group
+// of their own; empty names are ignored. Any groups not given and not
+// added by this package are not shown. All the parameters in the groups
+// are shown, including those which are not normally shown in the standard
+// help. The text is rendered in the same way as by the standard help so it
+// can be embedded in generated documentation. It returns an error if any
+// of the PSetOptFuncs returns an error, if any of the otherGroups has not
+// been added or if there are no groups to show.
+func WriteGroupHelp(
+	w io.Writer, width int, otherGroups []string, psof ...param.PSetOptFunc,
+) error {
+	h := groupHelper{
+		StdHelp: phelp.NewStdHelp(
+			phelp.SetStdWriter(w),
+			phelp.SetErrWriter(io.Discard)),
+	}
+	ps := param.NewSet(h)
+
+	for _, f := range psof {
+		if err := f(ps); err != nil {
+			return err
+		}
+	}
+
+	var groups []string
+
+	for _, g := range ps.GetGroups() {
+		if isSemverGroup(g.Name()) || slices.Contains(otherGroups, g.Name()) {
+			groups = append(groups, g.Name())
+		}
+	}
+
+	for _, name := range otherGroups {
+		if name == "" {
+			continue
+		}
+
+		if _, exists := ps.GetGroup(name); !exists {
+			return fmt.Errorf("the %q parameter group has not been added", name)
+		}
+	}
+
+	if len(groups) == 0 {
+		return errors.New("no " + semverGroupName +
+			" parameter groups have been added")
+	}
+
+	args := []string{
+		"-help-no-page",
+		"-help-all",
+		"-help-show", "params-grouped",
+		"-help-groups", strings.Join(groups, ","),
+	}
+	if width > 0 {
+		args = append(args, "-help-width", strconv.Itoa(width))
+	}
+
+	ps.Parse(args)
+	h.Help(ps)
+
+	return nil
+}
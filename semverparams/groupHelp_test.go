@@ -0,0 +1,146 @@
+package semverparams_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+var gfc = testhelper.GoldenFileCfg{
+	DirNames:               []string{"testdata", "help"},
+	Sfx:                    "txt",
+	UpdFlagName:            "upd-help-files",
+	KeepBadResultsFlagName: "keep-bad-results",
+}
+
+func init() {
+	gfc.AddUpdateFlag()
+	gfc.AddKeepBadResultsFlag()
+}
+
+func TestWriteGroupHelp(t *testing.T) {
+	// the group config file names are shown in the help so they must be
+	// the same wherever the tests are run
+	t.Setenv("HOME", "/home/user")
+	t.Setenv("XDG_CONFIG_HOME", "/home/user/.config")
+	t.Setenv("XDG_CONFIG_DIRS", "/etc/xdg")
+
+	svvPfx := semverparams.SemverVals{Prefix: "other", Desc: "other"}
+	svCksName := semverparams.SemverChecks{
+		Name: "other",
+		Desc: " for the other version",
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		width       int
+		otherGroups []string
+		psof        []param.PSetOptFunc
+	}{
+		{
+			ID: testhelper.MkID("semver"),
+			psof: func() []param.PSetOptFunc {
+				var svv semverparams.SemverVals
+
+				return []param.PSetOptFunc{
+					semverparams.AddSemverGroup,
+					svv.AddSemverParam(nil),
+					svv.AddIDParams(nil),
+				}
+			}(),
+		},
+		{
+			ID:    testhelper.MkID("semver-checks"),
+			width: 72,
+			psof: func() []param.PSetOptFunc {
+				var (
+					svv   semverparams.SemverVals
+					svCks semverparams.SemverChecks
+				)
+
+				return []param.PSetOptFunc{
+					semverparams.AddSemverGroup,
+					svv.AddSemverParam(&svCks),
+					svCks.AddCheckParams(),
+				}
+			}(),
+		},
+		{
+			ID: testhelper.MkID("prefix-and-name"),
+			psof: []param.PSetOptFunc{
+				semverparams.AddSemverGroup,
+				svvPfx.AddSemverParam(&svCksName),
+				svvPfx.AddIDParams(&svCksName),
+				svCksName.AddCheckParams(),
+			},
+		},
+		{
+			ID: testhelper.MkID("other-groups-ignored"),
+			psof: []param.PSetOptFunc{
+				semverparams.AddSemverGroup,
+				svCksName.AddCheckParams(),
+				func(ps *param.PSet) error {
+					ps.AddGroup("unrelated", "not shown")
+					return nil
+				},
+			},
+		},
+		{
+			ID:          testhelper.MkID("renamed-group"),
+			otherGroups: []string{"release"},
+			psof: func() []param.PSetOptFunc {
+				svv := semverparams.SemverVals{
+					GroupName: "release",
+					GroupDesc: "parameters for the release version",
+				}
+
+				return []param.PSetOptFunc{
+					svv.AddSemverParam(nil),
+					func(ps *param.PSet) error {
+						ps.AddGroup("unrelated", "not shown")
+						return nil
+					},
+				}
+			}(),
+		},
+		{
+			ID:          testhelper.MkID("unknown-group"),
+			otherGroups: []string{"nonesuch"},
+			ExpErr: testhelper.MkExpErr(
+				`the "nonesuch" parameter group has not been added`),
+			psof: []param.PSetOptFunc{semverparams.AddSemverGroup},
+		},
+		{
+			ID: testhelper.MkID("bad-psof"),
+			ExpErr: testhelper.MkExpErr(
+				`the "semver" parameter has already been added to this PSet`),
+			psof: func() []param.PSetOptFunc {
+				var svv semverparams.SemverVals
+
+				return []param.PSetOptFunc{
+					svv.AddSemverParam(nil),
+					svv.AddSemverParam(nil),
+				}
+			}(),
+		},
+		{
+			ID: testhelper.MkID("no-groups"),
+			ExpErr: testhelper.MkExpErr(
+				"no semver parameter groups have been added"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var b bytes.Buffer
+
+		err := semverparams.WriteGroupHelp(&b, tc.width, tc.otherGroups,
+			tc.psof...)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			gfc.Check(t, tc.IDStr(), tc.Name, b.Bytes())
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package semverparams
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ManifestFormat identifies the format of a project manifest file. The
+// format is determined from the name of the file.
+type ManifestFormat int
+
+// These are the supported manifest formats
+const (
+	ManifestUnknown ManifestFormat = iota
+	// ManifestVersionFile is a plain VERSION file; the version is the
+	// first non-blank line
+	ManifestVersionFile
+	// ManifestPackageJSON is an npm package.json file; the version is the
+	// value of the top-level "version" member
+	ManifestPackageJSON
+	// ManifestCargoTOML is a Rust Cargo.toml file; the version is the
+	// version key in the [package] or [workspace.package] table
+	ManifestCargoTOML
+	// ManifestPyProjectTOML is a Python pyproject.toml file; the version is
+	// the version key in the [project] or [tool.poetry] table
+	ManifestPyProjectTOML
+	// ManifestChartYAML is a Helm Chart.yaml file; the version is the value
+	// of the top-level version key
+	ManifestChartYAML
+)
+
+// manifestFileNames maps the base name of a manifest file to its format
+var manifestFileNames = map[string]ManifestFormat{
+	"VERSION":        ManifestVersionFile,
+	"package.json":   ManifestPackageJSON,
+	"Cargo.toml":     ManifestCargoTOML,
+	"pyproject.toml": ManifestPyProjectTOML,
+	"Chart.yaml":     ManifestChartYAML,
+}
+
+// String returns the name of the manifest format
+func (mf ManifestFormat) String() string {
+	switch mf {
+	case ManifestVersionFile:
+		return "VERSION"
+	case ManifestPackageJSON:
+		return "package.json"
+	case ManifestCargoTOML:
+		return "Cargo.toml"
+	case ManifestPyProjectTOML:
+		return "pyproject.toml"
+	case ManifestChartYAML:
+		return "Chart.yaml"
+	}
+
+	return "unknown"
+}
+
+// ManifestFormatFor returns the format of the named manifest file. It
+// returns an error if the format cannot be determined from the name.
+func ManifestFormatFor(filename string) (ManifestFormat, error) {
+	mf, ok := manifestFileNames[filepath.Base(filename)]
+	if !ok {
+		return ManifestUnknown,
+			fmt.Errorf("%q is not a recognised manifest file,"+
+				" the file name must be one of: %s",
+				filename, manifestFileNameList())
+	}
+
+	return mf, nil
+}
+
+// manifestFileNameList returns the recognised manifest file names as a
+// comma-separated list
+func manifestFileNameList() string {
+	return strings.Join([]string{
+		ManifestVersionFile.String(),
+		ManifestPackageJSON.String(),
+		ManifestCargoTOML.String(),
+		ManifestPyProjectTOML.String(),
+		ManifestChartYAML.String(),
+	}, ", ")
+}
+
+// ManifestVersion records the version read from a project manifest file
+// together with the location of the version text in the file.
+type ManifestVersion struct {
+	Filename string
+	Format   ManifestFormat
+
+	// Line is the line number (starting at 1) of the version text
+	Line int
+	// Start and End are the byte offsets of the version text in the file
+	Start int
+	End   int
+	// Text is the version text exactly as it appears in the file
+	Text string
+
+	// SemVer is the semantic version number parsed from the Text
+	SemVer semver.SV
+}
+
+// HasVPrefix returns true if the version text in the manifest starts with a
+// 'v'
+func (mv ManifestVersion) HasVPrefix() bool {
+	return strings.HasPrefix(mv.Text, "v")
+}
+
+// errNoManifestVersion is returned by the manifest parsers if no version
+// can be found
+var errNoManifestVersion = errors.New("no version found")
+
+// manifestParseErr records an error found while parsing a manifest file
+// together with the byte offset in the file where it was found
+type manifestParseErr struct {
+	offset int
+	msg    string
+}
+
+// Error returns the error message
+func (e manifestParseErr) Error() string { return e.msg }
+
+// ReadManifest reads the named manifest file and returns the version it
+// contains. The format of the file is determined by its name (see
+// ManifestFormatFor).
+func ReadManifest(filename string) (*ManifestVersion, error) {
+	content, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseManifest(filename, content)
+}
+
+// ParseManifest finds the version in the content of a manifest file. The
+// filename is used to determine the format and to give context in any
+// error. The version text is checked using the same rules as the SVSetter
+// except that the leading 'v' is optional. Any error will give the file
+// name and, where possible, the line number where the problem was found.
+func ParseManifest(filename string, content []byte) (*ManifestVersion, error) {
+	mf, err := ManifestFormatFor(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var start, end int
+
+	switch mf {
+	case ManifestVersionFile:
+		start, end, err = findVersionFileVersion(content)
+	case ManifestPackageJSON:
+		start, end, err = findPackageJSONVersion(content)
+	case ManifestCargoTOML:
+		start, end, err = findTOMLVersion(content, "package", "workspace.package")
+	case ManifestPyProjectTOML:
+		start, end, err = findTOMLVersion(content, "project", "tool.poetry")
+	case ManifestChartYAML:
+		start, end, err = findChartYAMLVersion(content)
+	}
+
+	if err != nil {
+		if mpe, ok := errors.AsType[manifestParseErr](err); ok {
+			return nil, fmt.Errorf("%s:%d: %w",
+				filename, lineNumber(content, mpe.offset), err)
+		}
+
+		return nil, fmt.Errorf("%s: %s: %w", filename, mf, err)
+	}
+
+	mv := &ManifestVersion{
+		Filename: filename,
+		Format:   mf,
+		Line:     lineNumber(content, start),
+		Start:    start,
+		End:      end,
+		Text:     string(content[start:end]),
+	}
+
+	err = setFromVersionText(&mv.SemVer, mv.Text)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%d: %w", filename, mv.Line, err)
+	}
+
+	return mv, nil
+}
+
+// lineNumber returns the line number (starting at 1) of the given offset
+func lineNumber(content []byte, offset int) int {
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
+
+// manifestLine holds a line from a manifest file with the byte offset of the
+// start of the line
+type manifestLine struct {
+	offset int
+	text   string
+}
+
+// manifestLines splits the content into lines, each line records its offset
+// in the content. Any trailing carriage return is removed from the line
+// text.
+func manifestLines(content []byte) []manifestLine {
+	var lines []manifestLine
+
+	offset := 0
+	for line := range bytes.Lines(content) {
+		text := strings.TrimRight(string(line), "\r\n")
+		lines = append(lines, manifestLine{offset: offset, text: text})
+		offset += len(line)
+	}
+
+	return lines
+}
+
+// findVersionFileVersion returns the offsets of the first non-blank line
+// (ignoring leading and trailing white space)
+func findVersionFileVersion(content []byte) (int, int, error) {
+	for _, l := range manifestLines(content) {
+		trimmed := strings.TrimSpace(l.text)
+		if trimmed == "" {
+			continue
+		}
+
+		start := l.offset + strings.Index(l.text, trimmed)
+
+		return start, start + len(trimmed), nil
+	}
+
+	return 0, 0, errNoManifestVersion
+}
+
+// skipJSONSpace returns the offset of the first non-space character at or
+// after i
+func skipJSONSpace(content []byte, i int) int {
+	for i < len(content) {
+		switch content[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+
+	return i
+}
+
+// jsonStringEnd returns the offset of the quote closing the JSON string
+// starting at offset start
+func jsonStringEnd(content []byte, start int) (int, error) {
+	for i := start + 1; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '"':
+			return i, nil
+		}
+	}
+
+	return 0, manifestParseErr{offset: start, msg: "unterminated string"}
+}
+
+// findPackageJSONVersion returns the offsets of the value of the top-level
+// "version" member of the JSON object in content. It is a minimal parser
+// which only tracks enough of the JSON structure to find the top-level
+// object keys.
+func findPackageJSONVersion(content []byte) (int, int, error) {
+	var containers []byte
+
+	expectKey := false
+
+	for i := 0; i < len(content); i++ {
+		switch c := content[i]; c {
+		case '{':
+			containers = append(containers, c)
+			expectKey = true
+		case '[':
+			containers = append(containers, c)
+			expectKey = false
+		case '}', ']':
+			if len(containers) == 0 {
+				return 0, 0,
+					manifestParseErr{offset: i, msg: "unbalanced " + string(c)}
+			}
+
+			containers = containers[:len(containers)-1]
+			expectKey = false
+		case ',':
+			expectKey = len(containers) > 0 &&
+				containers[len(containers)-1] == '{'
+		case '"':
+			end, err := jsonStringEnd(content, i)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			isTopLevelKey := expectKey && len(containers) == 1
+			expectKey = false
+
+			if isTopLevelKey && string(content[i+1:end]) == "version" {
+				return jsonVersionValue(content, end+1)
+			}
+
+			i = end
+		}
+	}
+
+	return 0, 0, errNoManifestVersion
+}
+
+// jsonVersionValue returns the offsets of the contents of the string value
+// following the "version" key which ends just before offset i
+func jsonVersionValue(content []byte, i int) (int, int, error) {
+	i = skipJSONSpace(content, i)
+	if i >= len(content) || content[i] != ':' {
+		return 0, 0, manifestParseErr{
+			offset: min(i, len(content)),
+			msg:    `missing ':' after the "version" key`,
+		}
+	}
+
+	i = skipJSONSpace(content, i+1)
+	if i >= len(content) || content[i] != '"' {
+		return 0, 0, manifestParseErr{
+			offset: min(i, len(content)),
+			msg:    `the "version" value is not a string`,
+		}
+	}
+
+	end, err := jsonStringEnd(content, i)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if bytes.IndexByte(content[i+1:end], '\\') >= 0 {
+		return 0, 0, manifestParseErr{
+			offset: i,
+			msg:    `the "version" value must not contain escape sequences`,
+		}
+	}
+
+	return i + 1, end, nil
+}
+
+// tomlKeyValue splits a TOML line of the form 'key = value' and returns the
+// key (with any quotes removed) and the offset of the value within the
+// line. It returns false if the line is not a key/value line.
+func tomlKeyValue(line string) (string, int, bool) {
+	key, _, ok := strings.Cut(line, "=")
+	if !ok {
+		return "", 0, false
+	}
+
+	valOffset := len(key) + 1
+	key = strings.Trim(strings.TrimSpace(key), `"'`)
+
+	rest := line[valOffset:]
+	valOffset += len(rest) - len(strings.TrimLeft(rest, " \t"))
+
+	return key, valOffset, true
+}
+
+// tomlStringValue returns the offsets of the contents of the quoted TOML
+// string at the start of val
+func tomlStringValue(val string) (int, int, bool) {
+	if val == "" || (val[0] != '"' && val[0] != '\'') {
+		return 0, 0, false
+	}
+
+	end := strings.IndexByte(val[1:], val[0])
+	if end < 0 {
+		return 0, 0, false
+	}
+
+	return 1, end + 1, true
+}
+
+// findTOMLVersion returns the offsets of the value of the version key in the
+// first of the given tables in which it appears. It is a minimal parser
+// which only understands table headers and simple key/value lines.
+func findTOMLVersion(content []byte, tables ...string) (int, int, error) {
+	table := ""
+
+	for _, l := range manifestLines(content) {
+		trimmed := strings.TrimSpace(l.text)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		if trimmed[0] == '[' {
+			table = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+
+		if !slices.Contains(tables, table) {
+			continue
+		}
+
+		key, valOffset, ok := tomlKeyValue(l.text)
+		if !ok {
+			continue
+		}
+
+		if key == "version.workspace" {
+			return 0, 0, manifestParseErr{
+				offset: l.offset,
+				msg:    "the version is inherited from the workspace",
+			}
+		}
+
+		if key != "version" {
+			continue
+		}
+
+		start, end, ok := tomlStringValue(l.text[valOffset:])
+		if !ok {
+			return 0, 0, manifestParseErr{
+				offset: l.offset,
+				msg:    "the version value is not a string",
+			}
+		}
+
+		return l.offset + valOffset + start, l.offset + valOffset + end, nil
+	}
+
+	return 0, 0, fmt.Errorf("%w in the [%s] table",
+		errNoManifestVersion, strings.Join(tables, "] or ["))
+}
+
+// findChartYAMLVersion returns the offsets of the value of the top-level
+// version key. It is a minimal parser which only looks at unindented
+// 'key: value' lines.
+func findChartYAMLVersion(content []byte) (int, int, error) {
+	const versionKey = "version:"
+
+	for _, l := range manifestLines(content) {
+		if !strings.HasPrefix(l.text, versionKey) {
+			continue
+		}
+
+		val := l.text[len(versionKey):]
+		valOffset := len(versionKey) + len(val) - len(strings.TrimLeft(val, " \t"))
+		val = strings.TrimLeft(val, " \t")
+
+		if start, end, ok := tomlStringValue(val); ok {
+			return l.offset + valOffset + start, l.offset + valOffset + end, nil
+		}
+
+		if idx := strings.Index(val, " #"); idx >= 0 {
+			val = val[:idx]
+		}
+
+		val = strings.TrimRight(val, " \t")
+		if val == "" {
+			return 0, 0, manifestParseErr{
+				offset: l.offset,
+				msg:    "the version value is empty",
+			}
+		}
+
+		return l.offset + valOffset, l.offset + valOffset + len(val), nil
+	}
+
+	return 0, 0, errNoManifestVersion
+}
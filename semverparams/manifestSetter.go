@@ -0,0 +1,53 @@
+package semverparams
+
+import (
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ManifestSetter is a parameter setter which will set a semantic version
+// number from the version given in a project manifest file. The parameter
+// value is the name of the manifest file. It satisfies the param.Setter
+// interface and so can be used when specifying a command line argument
+// using the param package.
+type ManifestSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *semver.SV
+}
+
+// SetWithVal reads the version from the named manifest file. It returns an
+// error if the file cannot be read, if no version can be found or if the
+// version is not a valid semantic version number. Only if there is no
+// error is the Value set.
+func (ms ManifestSetter) SetWithVal(_ string, paramVal string) error {
+	mv, err := ReadManifest(paramVal)
+	if err != nil {
+		return err
+	}
+
+	mv.SemVer.CopyInto(ms.Value)
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (ms ManifestSetter) AllowedValues() string {
+	return "the name of a project manifest file holding a " +
+		semver.Name + "." +
+		" The format of the file is determined from its name" +
+		" which must be one of: " + manifestFileNameList() + "." +
+		" The leading 'v' of the version is optional."
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (ms ManifestSetter) CurrentValue() string {
+	return ms.Value.String()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (ms ManifestSetter) CheckSetter(name string) {
+	if ms.Value == nil {
+		panic(name + ": ManifestSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,117 @@
+package semverparams
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ManifestUpdate describes how a new version should be written back to a
+// project manifest file. Only the version text is changed, the rest of the
+// file is preserved byte-for-byte.
+type ManifestUpdate struct {
+	// DryRun, if true, means that the file is left unchanged and a diff
+	// showing the change that would have been made is written to DiffW
+	// instead.
+	DryRun bool
+	// DiffW is the writer to which the dry-run diff is written. If it is
+	// nil the diff is written to the standard output.
+	DiffW io.Writer
+}
+
+// textFor returns the text to be written into the manifest in place of the
+// current version text. The leading 'v' is only given if the current
+// version text has one.
+func (mv ManifestVersion) textFor(sv semver.SV) string {
+	if mv.HasVPrefix() {
+		return sv.String()
+	}
+
+	return strings.TrimPrefix(sv.String(), "v")
+}
+
+// Replace returns a copy of the manifest content with the version text
+// replaced by the given version. The content must be the content from which
+// the ManifestVersion was parsed.
+func (mv ManifestVersion) Replace(content []byte, sv semver.SV) []byte {
+	newText := mv.textFor(sv)
+
+	newContent := make([]byte, 0, len(content)-len(mv.Text)+len(newText))
+	newContent = append(newContent, content[:mv.Start]...)
+	newContent = append(newContent, newText...)
+	newContent = append(newContent, content[mv.End:]...)
+
+	return newContent
+}
+
+// Write writes the version into the named manifest file. The file is
+// written atomically by writing a temporary file in the same directory and
+// renaming it over the original. Nothing is written if the version is
+// unchanged. If DryRun is set the file is not changed and a unified diff of
+// the change is written instead.
+func (mu ManifestUpdate) Write(filename string, sv semver.SV) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	mv, err := ParseManifest(filename, content)
+	if err != nil {
+		return err
+	}
+
+	if mv.textFor(sv) == mv.Text {
+		return nil
+	}
+
+	newContent := mv.Replace(content, sv)
+
+	if mu.DryRun {
+		w := mu.DiffW
+		if w == nil {
+			w = os.Stdout
+		}
+
+		return writeManifestDiff(w, mv, content, newContent)
+	}
+
+	return writeFileAtomic(filename, newContent, info.Mode().Perm())
+}
+
+// lineAt returns the whole line (without the line ending) which contains
+// the byte at the given offset
+func lineAt(content []byte, offset int) string {
+	start := bytes.LastIndexByte(content[:offset], '\n') + 1
+
+	end := bytes.IndexByte(content[offset:], '\n')
+	if end < 0 {
+		end = len(content)
+	} else {
+		end += offset
+	}
+
+	return strings.TrimSuffix(string(content[start:end]), "\r")
+}
+
+// writeManifestDiff writes a unified diff showing the change in the line
+// holding the version
+func writeManifestDiff(w io.Writer,
+	mv *ManifestVersion, oldContent, newContent []byte,
+) error {
+	_, err := fmt.Fprintf(w, "--- %s\n+++ %s\n@@ -%d +%d @@\n-%s\n+%s\n",
+		mv.Filename, mv.Filename,
+		mv.Line, mv.Line,
+		lineAt(oldContent, mv.Start),
+		lineAt(newContent, mv.Start))
+
+	return err
+}
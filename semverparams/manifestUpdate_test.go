@@ -0,0 +1,104 @@
+package semverparams_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestManifestUpdate(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		filename   string
+		content    string
+		sv         *semver.SV
+		dryRun     bool
+		expContent string
+		expDiff    string
+	}{
+		{
+			ID:         testhelper.MkID("VERSION file, keeps the v"),
+			filename:   "VERSION",
+			content:    "v1.2.3\r\n# comment\n",
+			sv:         semver.NewSVOrPanic(1, 3, 0, nil, nil),
+			expContent: "v1.3.0\r\n# comment\n",
+		},
+		{
+			ID:       testhelper.MkID("package.json"),
+			filename: "package.json",
+			content: "{\n  \"version\":  \"1.2.3\",\n" +
+				"  \"x\": {\"version\": \"1.2.3\"}\n}",
+			sv: semver.NewSVOrPanic(2, 0, 0, []string{"rc", "1"}, nil),
+			expContent: "{\n  \"version\":  \"2.0.0-rc.1\",\n" +
+				"  \"x\": {\"version\": \"1.2.3\"}\n}",
+		},
+		{
+			ID:         testhelper.MkID("Cargo.toml, dry run"),
+			filename:   "Cargo.toml",
+			content:    "[package]\nname = \"x\"\nversion = \"0.1.0\" # ver\n",
+			sv:         semver.NewSVOrPanic(0, 2, 0, nil, nil),
+			dryRun:     true,
+			expContent: "[package]\nname = \"x\"\nversion = \"0.1.0\" # ver\n",
+			expDiff: "--- DIR/Cargo.toml\n+++ DIR/Cargo.toml\n" +
+				"@@ -3 +3 @@\n" +
+				"-version = \"0.1.0\" # ver\n" +
+				"+version = \"0.2.0\" # ver\n",
+		},
+		{
+			ID:         testhelper.MkID("Chart.yaml, unchanged, dry run"),
+			filename:   "Chart.yaml",
+			content:    "version: 1.0.0\n",
+			sv:         semver.NewSVOrPanic(1, 0, 0, nil, nil),
+			dryRun:     true,
+			expContent: "version: 1.0.0\n",
+		},
+		{
+			ID:         testhelper.MkID("pyproject.toml, no version"),
+			ExpErr:     testhelper.MkExpErr("no version found"),
+			filename:   "pyproject.toml",
+			content:    "[project]\nname = \"x\"\n",
+			sv:         semver.NewSVOrPanic(1, 0, 0, nil, nil),
+			expContent: "[project]\nname = \"x\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, tc.filename)
+
+		if err := os.WriteFile(filename, []byte(tc.content), 0o600); err != nil {
+			t.Fatal("couldn't create the manifest file:", err)
+		}
+
+		diff := &bytes.Buffer{}
+		mu := semverparams.ManifestUpdate{DryRun: tc.dryRun, DiffW: diff}
+
+		err := mu.Write(filename, *tc.sv)
+		testhelper.CheckExpErr(t, err, tc)
+
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal("couldn't read the manifest file:", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "content",
+			string(content), tc.expContent)
+		testhelper.DiffString(t, tc.IDStr(), "diff",
+			string(bytes.ReplaceAll(diff.Bytes(), []byte(dir), []byte("DIR"))),
+			tc.expDiff)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal("couldn't read the test directory:", err)
+		}
+
+		testhelper.DiffInt(t, tc.IDStr(), "files in the directory",
+			len(entries), 1)
+	}
+}
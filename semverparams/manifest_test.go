@@ -0,0 +1,151 @@
+package semverparams_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParseManifest(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		filename string
+		content  string
+		expSV    string
+		expText  string
+		expLine  int
+	}{
+		{
+			ID:       testhelper.MkID("VERSION file"),
+			filename: "VERSION",
+			content:  "\n  1.2.3  \n",
+			expSV:    "v1.2.3",
+			expText:  "1.2.3",
+			expLine:  2,
+		},
+		{
+			ID:       testhelper.MkID("VERSION file, with v prefix"),
+			filename: "dir/VERSION",
+			content:  "v1.2.3-rc.1+b.42\n",
+			expSV:    "v1.2.3-rc.1+b.42",
+			expText:  "v1.2.3-rc.1+b.42",
+			expLine:  1,
+		},
+		{
+			ID:       testhelper.MkID("VERSION file, empty"),
+			ExpErr:   testhelper.MkExpErr("VERSION: VERSION: no version found"),
+			filename: "VERSION",
+			content:  "\n\n",
+		},
+		{
+			ID:       testhelper.MkID("package.json"),
+			filename: "package.json",
+			content: `{
+  "name": "x",
+  "scripts": {"version": "echo 0.0.1"},
+  "list": [{"version": "0.0.2"}],
+  "version" : "2.0.1"
+}`,
+			expSV:   "v2.0.1",
+			expText: "2.0.1",
+			expLine: 5,
+		},
+		{
+			ID:       testhelper.MkID("package.json, no top-level version"),
+			ExpErr:   testhelper.MkExpErr("package.json: no version found"),
+			filename: "package.json",
+			content:  `{"name": "x", "a": {"version": "1.0.0"}}`,
+		},
+		{
+			ID: testhelper.MkID("package.json, version not a string"),
+			ExpErr: testhelper.MkExpErr(
+				`package.json:2: the "version" value is not a string`),
+			filename: "package.json",
+			content:  "{\n\"version\": 1}",
+		},
+		{
+			ID:       testhelper.MkID("Cargo.toml"),
+			filename: "Cargo.toml",
+			content: `[dependencies]
+version = "9.9.9"
+
+[package]
+name = "x"
+version = "0.3.0" # the version
+`,
+			expSV:   "v0.3.0",
+			expText: "0.3.0",
+			expLine: 6,
+		},
+		{
+			ID: testhelper.MkID("Cargo.toml, workspace version"),
+			ExpErr: testhelper.MkExpErr(
+				"Cargo.toml:3: the version is inherited from the workspace"),
+			filename: "Cargo.toml",
+			content:  "[package]\nname = \"x\"\nversion.workspace = true\n",
+		},
+		{
+			ID:       testhelper.MkID("pyproject.toml, poetry"),
+			filename: "pyproject.toml",
+			content:  "[tool.poetry]\nversion = '1.0.0-alpha.1'\n",
+			expSV:    "v1.0.0-alpha.1",
+			expText:  "1.0.0-alpha.1",
+			expLine:  2,
+		},
+		{
+			ID: testhelper.MkID("pyproject.toml, no version"),
+			ExpErr: testhelper.MkExpErr(
+				"no version found in the [project] or [tool.poetry] table"),
+			filename: "pyproject.toml",
+			content:  "[project]\ndynamic = [\"version\"]\n",
+		},
+		{
+			ID:       testhelper.MkID("Chart.yaml"),
+			filename: "Chart.yaml",
+			content: `apiVersion: v2
+dependencies:
+  - name: y
+    version: 1.1.1
+version: 3.2.1 # chart version
+appVersion: "1.16.0"
+`,
+			expSV:   "v3.2.1",
+			expText: "3.2.1",
+			expLine: 5,
+		},
+		{
+			ID:       testhelper.MkID("Chart.yaml, quoted"),
+			filename: "Chart.yaml",
+			content:  `version: "3.2.1"`,
+			expSV:    "v3.2.1",
+			expText:  "3.2.1",
+			expLine:  1,
+		},
+		{
+			ID: testhelper.MkID("Chart.yaml, bad version"),
+			ExpErr: testhelper.MkExpErr("Chart.yaml:2: bad semantic version ID",
+				"cannot be split into major/minor/patch parts"),
+			filename: "Chart.yaml",
+			content:  "name: x\nversion: 3.2\n",
+		},
+		{
+			ID:       testhelper.MkID("unknown file"),
+			ExpErr:   testhelper.MkExpErr(`"setup.py" is not a recognised manifest file`),
+			filename: "setup.py",
+		},
+	}
+
+	for _, tc := range testCases {
+		mv, err := semverparams.ParseManifest(tc.filename, []byte(tc.content))
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "semver",
+				mv.SemVer.String(), tc.expSV)
+			testhelper.DiffString(t, tc.IDStr(), "text", mv.Text, tc.expText)
+			testhelper.DiffString(t, tc.IDStr(), "text at offsets",
+				tc.content[mv.Start:mv.End], tc.expText)
+			testhelper.DiffInt(t, tc.IDStr(), "line", mv.Line, tc.expLine)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package semverparams
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// OutputFormat gives the format in which a version is written for use by
+// CI steps
+type OutputFormat string
+
+// These are the available output formats
+const (
+	// OutputDotenv writes KEY=value lines, suitable for a .env file
+	OutputDotenv OutputFormat = "dotenv"
+	// OutputGitHub writes KEY=value lines, suitable for the GitHub Actions
+	// $GITHUB_OUTPUT file. The lines are appended to any existing content
+	OutputGitHub OutputFormat = "github"
+	// OutputJSON writes a JSON object
+	OutputJSON OutputFormat = "json"
+)
+
+// gitHubOutputEnvVar is the name of the environment variable giving the
+// GitHub Actions output file
+const gitHubOutputEnvVar = "GITHUB_OUTPUT"
+
+// outputStdout is the output file name meaning the standard output
+const outputStdout = "-"
+
+// outputFilePerm is the permission given to a newly created output file
+const outputFilePerm = 0o644
+
+// VersionOutput holds a version and its parts as written for use by CI
+// steps
+type VersionOutput struct {
+	Version      string `json:"version"`
+	Core         string `json:"core"`
+	Major        int    `json:"major"`
+	Minor        int    `json:"minor"`
+	Patch        int    `json:"patch"`
+	PreRelease   string `json:"prerelease"`
+	Build        string `json:"build"`
+	IsPreRelease bool   `json:"isPrerelease"`
+}
+
+// NewVersionOutput returns the VersionOutput for the semantic version number
+func NewVersionOutput(sv *semver.SV) VersionOutput {
+	return VersionOutput{
+		Version: sv.String(),
+		Core: fmt.Sprintf("%d.%d.%d",
+			sv.Major(), sv.Minor(), sv.Patch()),
+		Major:        sv.Major(),
+		Minor:        sv.Minor(),
+		Patch:        sv.Patch(),
+		PreRelease:   strings.Join(sv.PreRelIDs(), "."),
+		Build:        strings.Join(sv.BuildIDs(), "."),
+		IsPreRelease: len(sv.PreRelIDs()) > 0,
+	}
+}
+
+// OutputVar is a named value as written in the dotenv and github formats
+type OutputVar struct {
+	Name  string
+	Value string
+}
+
+// Vars returns the values as named variables, in a fixed order
+func (vo VersionOutput) Vars() []OutputVar {
+	return []OutputVar{
+		{"VERSION", vo.Version},
+		{"VERSION_CORE", vo.Core},
+		{"VERSION_MAJOR", strconv.Itoa(vo.Major)},
+		{"VERSION_MINOR", strconv.Itoa(vo.Minor)},
+		{"VERSION_PATCH", strconv.Itoa(vo.Patch)},
+		{"VERSION_PRERELEASE", vo.PreRelease},
+		{"VERSION_BUILD", vo.Build},
+		{"VERSION_IS_PRERELEASE", strconv.FormatBool(vo.IsPreRelease)},
+	}
+}
+
+// Marshal returns the VersionOutput in the given format
+func (vo VersionOutput) Marshal(format OutputFormat) ([]byte, error) {
+	switch format {
+	case OutputDotenv, OutputGitHub:
+		var b bytes.Buffer
+
+		for _, v := range vo.Vars() {
+			b.WriteString(v.Name + "=" + v.Value + "\n")
+		}
+
+		return b.Bytes(), nil
+	case OutputJSON:
+		content, err := json.MarshalIndent(vo, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(content, '\n'), nil
+	}
+
+	return nil, fmt.Errorf("unknown output format: %q", format)
+}
+
+// WriteVersionOutput writes the semantic version number and its parts in
+// the given format to the named file. If the file name is "-" the output is
+// written to the standard output. If the file name is empty and the format
+// is "github" the file named by the GITHUB_OUTPUT environment variable is
+// used. A file is written atomically; for the "github" format the output
+// is appended to any existing content, otherwise it replaces it.
+func WriteVersionOutput(format OutputFormat, filename string, sv *semver.SV,
+) error {
+	content, err := NewVersionOutput(sv).Marshal(format)
+	if err != nil {
+		return err
+	}
+
+	if filename == "" && format == OutputGitHub {
+		filename = os.Getenv(gitHubOutputEnvVar)
+		if filename == "" {
+			return fmt.Errorf("no output file was given and %s is not set",
+				gitHubOutputEnvVar)
+		}
+	}
+
+	if filename == "" || filename == outputStdout {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+
+	return writeOutputFile(filename, content, format == OutputGitHub)
+}
+
+// writeOutputFile writes the content atomically to the named file, keeping
+// the permissions of any existing file. If appendContent is true the
+// content is added to the end of any existing content.
+func writeOutputFile(filename string, content []byte, appendContent bool,
+) error {
+	perm := os.FileMode(outputFilePerm)
+
+	info, err := os.Stat(filename)
+
+	switch {
+	case err == nil:
+		perm = info.Mode().Perm()
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	if appendContent && err == nil {
+		existing, err := os.ReadFile(filename) //nolint:gosec
+		if err != nil {
+			return err
+		}
+
+		if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+			existing = append(existing, '\n')
+		}
+
+		content = append(existing, content...)
+	}
+
+	return writeFileAtomic(filename, content, perm)
+}
+
+// WriteOutput writes the SemVer as given by the semver-output parameters
+// (see AddOutputParams). It does nothing if no output format has been
+// given. It should be called once the parameters have been parsed and the
+// SemVer has its final value.
+func (svv SemverVals) WriteOutput() error {
+	if svv.OutputFormat == "" {
+		return nil
+	}
+
+	return WriteVersionOutput(svv.OutputFormat, svv.OutputFile, &svv.SemVer)
+}
+
+// AddOutputParams returns a function that will add parameters for setting
+// the OutputFormat and OutputFile to the passed PSet. The output itself is
+// written by the WriteOutput method.
+func (svv *SemverVals) AddOutputParams() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svv.Prefix != "" {
+			prefix = svv.Prefix + "-"
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		var (
+			outputParamName     = prefix + "semver-output"
+			outputFileParamName = prefix + "semver-output-file"
+		)
+
+		ps.Add(outputParamName,
+			psetter.Enum[OutputFormat]{
+				Value: &svv.OutputFormat,
+				AllowedVals: psetter.AllowedVals[OutputFormat]{
+					OutputDotenv: "KEY=value lines for a .env file",
+					OutputGitHub: "KEY=value lines appended to the" +
+						" GitHub Actions $" + gitHubOutputEnvVar + " file",
+					OutputJSON: "a JSON object",
+				},
+				AllowInvalidInitialValue: true,
+			},
+			"write the "+semver.Name+" and its parts"+
+				" (VERSION, VERSION_MAJOR, VERSION_PRERELEASE and so on)"+
+				" in a form suitable for use by CI steps",
+			param.GroupName(groupName),
+			param.SeeAlso(outputFileParamName),
+		)
+
+		ps.Add(outputFileParamName,
+			psetter.String[string]{Value: &svv.OutputFile},
+			"the file to which the "+outputParamName+" output is written."+
+				" The file is written atomically."+
+				" If the name is '"+outputStdout+"', or it is not given,"+
+				" the output is written to the standard output, except"+
+				" for the '"+string(OutputGitHub)+"' format which uses"+
+				" the $"+gitHubOutputEnvVar+" file",
+			param.GroupName(groupName),
+			param.SeeAlso(outputParamName),
+		)
+
+		return nil
+	}
+}
@@ -0,0 +1,206 @@
+package semverparams_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestVersionOutputMarshal(t *testing.T) {
+	sv := semver.NewSVOrPanic(1, 2, 3, []string{"rc", "1"}, []string{"b7"})
+	vo := semverparams.NewVersionOutput(sv)
+
+	const expVars = "VERSION=v1.2.3-rc.1+b7\n" +
+		"VERSION_CORE=1.2.3\n" +
+		"VERSION_MAJOR=1\n" +
+		"VERSION_MINOR=2\n" +
+		"VERSION_PATCH=3\n" +
+		"VERSION_PRERELEASE=rc.1\n" +
+		"VERSION_BUILD=b7\n" +
+		"VERSION_IS_PRERELEASE=true\n"
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		format semverparams.OutputFormat
+		expOut string
+	}{
+		{
+			ID:     testhelper.MkID("dotenv"),
+			format: semverparams.OutputDotenv,
+			expOut: expVars,
+		},
+		{
+			ID:     testhelper.MkID("github"),
+			format: semverparams.OutputGitHub,
+			expOut: expVars,
+		},
+		{
+			ID:     testhelper.MkID("json"),
+			format: semverparams.OutputJSON,
+			expOut: `{
+  "version": "v1.2.3-rc.1+b7",
+  "core": "1.2.3",
+  "major": 1,
+  "minor": 2,
+  "patch": 3,
+  "prerelease": "rc.1",
+  "build": "b7",
+  "isPrerelease": true
+}
+`,
+		},
+		{
+			ID:     testhelper.MkID("unknown format"),
+			ExpErr: testhelper.MkExpErr(`unknown output format: "xml"`),
+			format: "xml",
+		},
+	}
+
+	for _, tc := range testCases {
+		out, err := vo.Marshal(tc.format)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "output",
+				string(out), tc.expOut)
+		}
+	}
+}
+
+func TestWriteVersionOutput(t *testing.T) {
+	dir := t.TempDir()
+	sv := semver.NewSVOrPanic(2, 0, 0, nil, nil)
+
+	const expVars = "VERSION=v2.0.0\n" +
+		"VERSION_CORE=2.0.0\n" +
+		"VERSION_MAJOR=2\n" +
+		"VERSION_MINOR=0\n" +
+		"VERSION_PATCH=0\n" +
+		"VERSION_PRERELEASE=\n" +
+		"VERSION_BUILD=\n" +
+		"VERSION_IS_PRERELEASE=false\n"
+
+	ghFile := filepath.Join(dir, "github.out")
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		format   semverparams.OutputFormat
+		filename string
+		ghEnvVal string
+		initial  string
+		checked  string
+		expOut   string
+	}{
+		{
+			ID:       testhelper.MkID("dotenv, replaces the file"),
+			format:   semverparams.OutputDotenv,
+			filename: filepath.Join(dir, "version.env"),
+			initial:  "OLD=1\n",
+			expOut:   expVars,
+		},
+		{
+			ID:       testhelper.MkID("github, appends to the file"),
+			format:   semverparams.OutputGitHub,
+			filename: filepath.Join(dir, "gh.txt"),
+			initial:  "other=x",
+			expOut:   "other=x\n" + expVars,
+		},
+		{
+			ID:       testhelper.MkID("github, file from the environment"),
+			format:   semverparams.OutputGitHub,
+			ghEnvVal: ghFile,
+			checked:  ghFile,
+			expOut:   expVars,
+		},
+		{
+			ID: testhelper.MkID("github, no file"),
+			ExpErr: testhelper.MkExpErr(
+				"no output file was given and GITHUB_OUTPUT is not set"),
+			format: semverparams.OutputGitHub,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Setenv("GITHUB_OUTPUT", tc.ghEnvVal)
+
+		if tc.initial != "" {
+			err := os.WriteFile(tc.filename, []byte(tc.initial), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := semverparams.WriteVersionOutput(tc.format, tc.filename, sv)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		checked := tc.filename
+		if tc.checked != "" {
+			checked = tc.checked
+		}
+
+		content, err := os.ReadFile(checked) //nolint:gosec
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Error("cannot read the output file:", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "file content",
+			string(content), tc.expOut)
+
+		if tc.initial != "" {
+			info, err := os.Stat(checked)
+			if err == nil && info.Mode().Perm() != 0o600 {
+				t.Log(tc.IDStr())
+				t.Error("the file permissions have changed:", info.Mode())
+			}
+		}
+	}
+}
+
+func TestOutputParams(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "version.json")
+
+	svv := semverparams.SemverVals{}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+		svv.AddOutputParams(),
+	)
+	ps.Parse([]string{
+		"-semver", "v0.3.1",
+		"-semver-output", "json",
+		"-semver-output-file", outFile,
+	})
+
+	if errMap := ps.Errors(); len(errMap) != 0 {
+		t.Fatal("unexpected errors:", errMap)
+	}
+
+	if err := svv.WriteOutput(); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	content, err := os.ReadFile(outFile) //nolint:gosec
+	if err != nil {
+		t.Fatal("cannot read the output file:", err)
+	}
+
+	expOut, _ := semverparams.NewVersionOutput(&svv.SemVer).
+		Marshal(semverparams.OutputJSON)
+	testhelper.DiffString(t, "output params", "file content",
+		string(content), string(expOut))
+
+	svv = semverparams.SemverVals{}
+	if err := svv.WriteOutput(); err != nil {
+		t.Error("unexpected error with no output format:", err)
+	}
+}
@@ -0,0 +1,177 @@
+package semverparams
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/location.mod/location"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// DeprecatedAlias gives an old name for a parameter. The old name can still
+// be used to set the parameter but a warning naming the replacement is
+// written to the ErrW of the SemverVals or SemverChecks each time it is
+// used.
+type DeprecatedAlias struct {
+	// Name is the old name of the parameter. Any prefix is applied in the
+	// same way as for the other names.
+	Name string
+
+	// ErrorFrom, if not nil, is the version of the program (as given by
+	// ProgSemver) from which using the old name is reported as an error
+	// rather than a warning. If the program version cannot be found the
+	// old name only gives a warning.
+	ErrorFrom *semver.SV
+}
+
+// ParamSpec allows the name, alternative names and help text of a parameter
+// to be changed from the default values. Any prefix (the Prefix of a
+// SemverVals or the Name of a SemverChecks) is applied to the names given
+// here in the same way as to the default names.
+type ParamSpec struct {
+	// Name, if not empty, replaces the default name of the parameter
+	Name string
+
+	// AltNames, if not nil, replaces the default alternative names of the
+	// parameter. To have no alternative names set it to an empty, non-nil,
+	// slice.
+	AltNames []string
+
+	// Help, if not empty, replaces the default help text of the parameter
+	Help string
+
+	// Deprecated gives old names of the parameter which are still accepted
+	// but which give a warning, see DeprecatedAlias. They are added to the
+	// alternative names of the parameter.
+	Deprecated []DeprecatedAlias
+}
+
+// paramNames holds the names and help text to be used when adding a
+// parameter
+type paramNames struct {
+	name       string
+	altNames   []string
+	help       string
+	deprecated []DeprecatedAlias
+}
+
+// resolve returns the names and help text to be used for the parameter,
+// taking the default values for any not given in the ParamSpec and applying
+// the prefix to the names
+func (spec ParamSpec) resolve(
+	prefix, dfltName string, dfltAltNames []string, dfltHelp string,
+) paramNames {
+	pn := paramNames{
+		name:     prefix + dfltName,
+		altNames: make([]string, 0, len(dfltAltNames)),
+		help:     dfltHelp,
+	}
+
+	if spec.Name != "" {
+		pn.name = prefix + spec.Name
+	}
+
+	if spec.AltNames != nil {
+		dfltAltNames = spec.AltNames
+	}
+
+	for _, an := range dfltAltNames {
+		pn.altNames = append(pn.altNames, prefix+an)
+	}
+
+	if spec.Help != "" {
+		pn.help = spec.Help
+	}
+
+	if len(spec.Deprecated) > 0 {
+		oldNames := make([]string, 0, len(spec.Deprecated))
+
+		for _, da := range spec.Deprecated {
+			da.Name = prefix + da.Name
+			pn.deprecated = append(pn.deprecated, da)
+			pn.altNames = append(pn.altNames, da.Name)
+			oldNames = append(oldNames, da.Name)
+		}
+
+		names := "name " + oldNames[0] + " is"
+		if len(oldNames) > 1 {
+			names = "names " + strings.Join(oldNames, ", ") + " are"
+		}
+
+		pn.help += "\n\nThe parameter " + names +
+			" deprecated, use " + pn.name + " instead"
+	}
+
+	return pn
+}
+
+// deprecationAction returns an ActionFunc which reports the use of any of
+// the deprecated names. It writes a warning to w unless the program version
+// has reached the version from which the name is an error in which case it
+// returns an error.
+func (pn paramNames) deprecationAction(w io.Writer) param.ActionFunc {
+	return func(loc location.L, _ *param.BaseParam, paramVals []string) error {
+		if len(pn.deprecated) == 0 || len(paramVals) == 0 {
+			return nil
+		}
+
+		i := slices.IndexFunc(pn.deprecated, func(da DeprecatedAlias) bool {
+			return da.Name == paramVals[0]
+		})
+		if i < 0 {
+			return nil
+		}
+
+		da := pn.deprecated[i]
+		msg := fmt.Sprintf("the parameter name %q is deprecated, use %q instead",
+			da.Name, pn.name)
+
+		if da.ErrorFrom != nil {
+			if progSV, err := ProgSemver(); err == nil &&
+				!semver.Less(progSV, da.ErrorFrom) {
+				return fmt.Errorf(
+					"the parameter name %q is no longer supported"+
+						" (since %s), use %q instead",
+					da.Name, da.ErrorFrom, pn.name)
+			}
+
+			msg += fmt.Sprintf(" (it will be an error from %s)", da.ErrorFrom)
+		}
+
+		fmt.Fprintf(w, "Warning: %s\nAt: %s\n", msg, loc)
+
+		return nil
+	}
+}
+
+// checkParamNames checks that all the names of the parameters are valid,
+// that none of them is used more than once and that none of them is already
+// in use in the PSet. It returns an error if any check fails.
+func checkParamNames(ps *param.PSet, pns ...paramNames) error {
+	seen := map[string]bool{}
+
+	for _, pn := range pns {
+		for _, name := range append([]string{pn.name}, pn.altNames...) {
+			if err := param.ParameterNameCheck(name); err != nil {
+				return err
+			}
+
+			if seen[name] {
+				return fmt.Errorf("the parameter name %q is used more than once",
+					name)
+			}
+
+			seen[name] = true
+
+			if _, err := ps.GetParamByName(name); err == nil {
+				return fmt.Errorf("the parameter name %q is already in use",
+					name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package semverparams_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestParamSpec(t *testing.T) {
+	type expParam struct {
+		altNames []string
+		help     string
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		psof      func() []param.PSetOptFunc
+		expParams map[string]expParam
+	}{
+		{
+			ID: testhelper.MkID("house style names"),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					SemverParam: semverparams.ParamSpec{
+						Name:     "version",
+						AltNames: []string{"ver"},
+						Help:     "the release version",
+					},
+					SemverFromParam: semverparams.ParamSpec{
+						Name: "version-from",
+					},
+				}
+
+				return []param.PSetOptFunc{svv.AddSemverParam(nil)}
+			},
+			expParams: map[string]expParam{
+				"version": {
+					altNames: []string{"version", "ver"},
+					help:     "the release version",
+				},
+				"version-from": {
+					altNames: []string{"version-from"},
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("prefixed, no alt names"),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					Prefix:         "next",
+					PreRelIDsParam: semverparams.ParamSpec{AltNames: []string{}},
+					BuildIDsParam:  semverparams.ParamSpec{Name: "build"},
+				}
+
+				return []param.PSetOptFunc{svv.AddIDParams(nil)}
+			},
+			expParams: map[string]expParam{
+				"next-pre-rel-IDs": {
+					altNames: []string{"next-pre-rel-IDs"},
+				},
+				"next-build": {
+					altNames: []string{"next-build", "next-bldIDs"},
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("check params"),
+			psof: func() []param.PSetOptFunc {
+				svCks := &semverparams.SemverChecks{
+					Name: "rel",
+					PreRelIDChecksParam: semverparams.ParamSpec{
+						Name:     "pre-checks",
+						AltNames: []string{"pc"},
+					},
+				}
+
+				return []param.PSetOptFunc{svCks.AddCheckParams()}
+			},
+			expParams: map[string]expParam{
+				"rel-pre-checks": {
+					altNames: []string{"rel-pre-checks", "rel-pc"},
+				},
+				"rel-build-ID-checks": {
+					altNames: []string{"rel-build-ID-checks", "rel-bldID-checks"},
+				},
+			},
+		},
+		{
+			ID: testhelper.MkID("name repeated"),
+			ExpErr: testhelper.MkExpErr(
+				`the parameter name "ver" is used more than once`),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					SemverParam: semverparams.ParamSpec{
+						Name:     "version",
+						AltNames: []string{"ver"},
+					},
+					SemverFromParam: semverparams.ParamSpec{Name: "ver"},
+				}
+
+				return []param.PSetOptFunc{svv.AddSemverParam(nil)}
+			},
+		},
+		{
+			ID: testhelper.MkID("name already in use"),
+			ExpErr: testhelper.MkExpErr(
+				`the parameter name "version" is already in use`),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					SemverParam: semverparams.ParamSpec{Name: "version"},
+				}
+
+				return []param.PSetOptFunc{
+					func(ps *param.PSet) error {
+						ps.Add("version", psetter.Nil{}, "show the version")
+						return nil
+					},
+					svv.AddSemverParam(nil),
+				}
+			},
+		},
+		{
+			ID: testhelper.MkID("bad name"),
+			ExpErr: testhelper.MkExpErr(
+				`the parameter name "1st-build" is invalid`),
+			psof: func() []param.PSetOptFunc {
+				svv := &semverparams.SemverVals{
+					BuildIDsParam: semverparams.ParamSpec{
+						AltNames: []string{"1st-build"},
+					},
+				}
+
+				return []param.PSetOptFunc{svv.AddIDParams(nil)}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ps := paramset.NewNoHelpNoExitNoErrRpt()
+
+		var err error
+
+		for _, f := range tc.psof() {
+			if err = f(ps); err != nil {
+				break
+			}
+		}
+
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		for name, exp := range tc.expParams {
+			p, err := ps.GetParamByName(name)
+			if err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: cannot find the parameter %q: %v", name, err)
+
+				continue
+			}
+
+			testhelper.DiffStringSlice(t, tc.IDStr(), name+" alt names",
+				p.AltNames(), exp.altNames)
+
+			if exp.help != "" {
+				testhelper.DiffString(t, tc.IDStr(), name+" help",
+					p.Description(), exp.help)
+			}
+		}
+	}
+}
+
+func TestDeprecatedAlias(t *testing.T) {
+	defer func(v string) {
+		semverparams.ProgVersion = v
+	}(semverparams.ProgVersion)
+
+	semverparams.ProgVersion = "v1.5.0"
+
+	const loc = "\nAt: [command line]: Supplied Parameter:2:" +
+		` "-rel-version" "v1.2.3"`
+
+	testCases := []struct {
+		testhelper.ID
+		args       []string
+		errorFrom  *semver.SV
+		expErrs    errutil.ErrMap
+		expWarning string
+		expSemVer  string
+	}{
+		{
+			ID:        testhelper.MkID("new name, no warning"),
+			args:      []string{"-version", "v1.2.3"},
+			expSemVer: "v1.2.3",
+		},
+		{
+			ID:   testhelper.MkID("old name, warning"),
+			args: []string{"-rel-version", "v1.2.3"},
+			expWarning: `Warning: the parameter name "rel-version"` +
+				` is deprecated, use "version" instead` + loc + "\n",
+			expSemVer: "v1.2.3",
+		},
+		{
+			ID:        testhelper.MkID("old name, error in future"),
+			args:      []string{"-rel-version", "v1.2.3"},
+			errorFrom: semver.NewSVOrPanic(2, 0, 0, nil, nil),
+			expWarning: `Warning: the parameter name "rel-version"` +
+				` is deprecated, use "version" instead` +
+				` (it will be an error from v2.0.0)` + loc + "\n",
+			expSemVer: "v1.2.3",
+		},
+		{
+			ID:        testhelper.MkID("old name, error now"),
+			args:      []string{"-rel-version", "v1.2.3"},
+			errorFrom: semver.NewSVOrPanic(1, 5, 0, nil, nil),
+			expErrs: errutil.ErrMap{
+				"version": []error{
+					errors.New(`the parameter name "rel-version" is no longer` +
+						` supported (since v1.5.0), use "version" instead` +
+						loc),
+				},
+			},
+			expSemVer: "v1.2.3",
+		},
+	}
+
+	for _, tc := range testCases {
+		var warnings bytes.Buffer
+
+		svv := semverparams.SemverVals{
+			ErrW: &warnings,
+			SemverParam: semverparams.ParamSpec{
+				Name: "version",
+				Deprecated: []semverparams.DeprecatedAlias{
+					{Name: "rel-version", ErrorFrom: tc.errorFrom},
+				},
+			},
+		}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(svv.AddSemverParam(nil))
+		ps.Parse(tc.args)
+
+		expErrs := tc.expErrs
+		if expErrs == nil {
+			expErrs = errutil.ErrMap{}
+		}
+
+		if err := expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error("\t: unexpected errors:", err)
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "warning",
+			warnings.String(), tc.expWarning)
+		testhelper.DiffString(t, tc.IDStr(), "semver",
+			svv.SemVer.String(), tc.expSemVer)
+	}
+
+	var warnings bytes.Buffer
+
+	svCks := semverparams.SemverChecks{
+		PreRelIDChecksParam: semverparams.ParamSpec{
+			Deprecated: []semverparams.DeprecatedAlias{{Name: "prc"}},
+		},
+		ErrW: &warnings,
+	}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(svCks.AddCheckParams())
+	ps.Parse([]string{"-prc", "Length(EQ(2))"})
+
+	testhelper.DiffString(t, "SemverChecks", "warning", warnings.String(),
+		`Warning: the parameter name "prc" is deprecated,`+
+			` use "pre-rel-ID-checks" instead`+
+			"\nAt: [command line]: Supplied Parameter:2:"+
+			` "-prc" "Length(EQ(2))"`+"\n")
+
+	p, err := paramset.NewNoHelpNoExitNoErrRpt(
+		(&semverparams.SemverVals{
+			SemverParam: semverparams.ParamSpec{
+				Deprecated: []semverparams.DeprecatedAlias{{Name: "sv"}},
+			},
+		}).AddSemverParam(nil)).GetParamByName("semver")
+	if err != nil {
+		t.Fatal("cannot find the semver parameter:", err)
+	}
+
+	testhelper.DiffString(t, "deprecated alias", "help",
+		p.Description(),
+		"specify the semantic version ID to be used"+
+			"\n\nThe parameter name sv is deprecated, use semver instead")
+}
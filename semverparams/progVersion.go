@@ -0,0 +1,127 @@
+package semverparams
+
+import (
+	"errors"
+	"runtime/debug"
+	"time"
+
+	"github.com/nickwells/param.mod/v7/paction"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// ProgVersion, if not empty, is used as the version of the program in
+// preference to the version recorded in the build information. It is
+// intended to be set when the program is built, for instance:
+//
+//	go build -ldflags \
+//	  "-X github.com/nickwells/semverparams.mod/v6/semverparams.ProgVersion=v1.2.3"
+//
+// The leading 'v' is optional.
+var ProgVersion string
+
+// develVersion is the main module version recorded in the build
+// information when the program is built from a local checkout
+const develVersion = "(devel)"
+
+// develPreRelID is the pre-release ID given to a program version built from
+// a local checkout
+const develPreRelID = "devel"
+
+// revisionIDLen is the number of characters of the VCS revision used in the
+// build IDs of a program version built from a local checkout
+const revisionIDLen = 12
+
+// ProgSemver returns the version of the program. This is taken from
+// ProgVersion if it has been set and from the build information otherwise
+// (see BuildInfoSemver).
+func ProgSemver() (*semver.SV, error) {
+	if ProgVersion != "" {
+		sv := &semver.SV{}
+		if err := setFromVersionText(sv, ProgVersion); err != nil {
+			return nil, err
+		}
+
+		return sv, nil
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil, errors.New("the program has no build information")
+	}
+
+	return BuildInfoSemver(bi)
+}
+
+// BuildInfoSemver returns the version of the main module given in the build
+// information. If the main module version is "(devel)", as it is when the
+// program is built from a local checkout, the version is v0.0.0 with a
+// pre-release ID of "devel" and build IDs taken from the VCS settings: the
+// commit time, the revision and "dirty" if there were uncommitted changes.
+func BuildInfoSemver(bi *debug.BuildInfo) (*semver.SV, error) {
+	if bi.Main.Version != develVersion && bi.Main.Version != "" {
+		sv := &semver.SV{}
+		if err := setFromVersionText(sv, bi.Main.Version); err != nil {
+			return nil, err
+		}
+
+		return sv, nil
+	}
+
+	var vcsTime, vcsRevision string
+
+	var vcsModified bool
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.time":
+			vcsTime = s.Value
+		case "vcs.revision":
+			vcsRevision = s.Value
+		case "vcs.modified":
+			vcsModified = s.Value == "true"
+		}
+	}
+
+	var buildIDs []string
+
+	if t, err := time.Parse(time.RFC3339, vcsTime); err == nil {
+		buildIDs = append(buildIDs, t.UTC().Format("20060102150405"))
+	}
+
+	if vcsRevision != "" {
+		buildIDs = append(buildIDs, vcsRevision[:min(len(vcsRevision),
+			revisionIDLen)])
+	}
+
+	if vcsModified {
+		buildIDs = append(buildIDs, "dirty")
+	}
+
+	return semver.NewSV(0, 0, 0, []string{develPreRelID}, buildIDs)
+}
+
+// AddVersionParam will add a parameter to the PSet which, when given, will
+// print the version of the program (see ProgSemver) and exit.
+func AddVersionParam(ps *param.PSet) error {
+	_ = AddSemverGroup(ps)
+
+	msg := "the program version is unknown: "
+
+	sv, err := ProgSemver()
+	if err != nil {
+		msg += err.Error()
+	} else {
+		msg = sv.String()
+	}
+
+	ps.Add("version", psetter.Nil{},
+		"show the "+semver.Name+" of this program and exit",
+		param.GroupName(semverGroupName),
+		param.Attrs(param.CommandLineOnly),
+		param.PostAction(paction.ReportAndExit(msg+"\n")),
+	)
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package semverparams_test
+
+import (
+	"os"
+	"os/exec"
+	"runtime/debug"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestBuildInfoSemver(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		bi    debug.BuildInfo
+		expSV string
+	}{
+		{
+			ID:    testhelper.MkID("released version"),
+			bi:    debug.BuildInfo{Main: debug.Module{Version: "v1.2.3"}},
+			expSV: "v1.2.3",
+		},
+		{
+			ID: testhelper.MkID("pseudo-version"),
+			bi: debug.BuildInfo{Main: debug.Module{
+				Version: "v0.0.0-20260101120000-0123456789ab+dirty",
+			}},
+			expSV: "v0.0.0-20260101120000-0123456789ab+dirty",
+		},
+		{
+			ID: testhelper.MkID("bad version"),
+			ExpErr: testhelper.MkExpErr("bad semantic version ID",
+				"cannot be split into major/minor/patch parts"),
+			bi: debug.BuildInfo{Main: debug.Module{Version: "v1.2"}},
+		},
+		{
+			ID:    testhelper.MkID("devel, no VCS settings"),
+			bi:    debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			expSV: "v0.0.0-devel",
+		},
+		{
+			ID: testhelper.MkID("devel, with VCS settings"),
+			bi: debug.BuildInfo{
+				Main: debug.Module{Version: "(devel)"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs", Value: "git"},
+					{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+					{Key: "vcs.time", Value: "2026-01-02T03:04:05+01:00"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			expSV: "v0.0.0-devel+20260102020405.0123456789ab.dirty",
+		},
+		{
+			ID: testhelper.MkID("devel, unmodified"),
+			bi: debug.BuildInfo{
+				Main: debug.Module{Version: "(devel)"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "0123"},
+					{Key: "vcs.modified", Value: "false"},
+				},
+			},
+			expSV: "v0.0.0-devel+0123",
+		},
+	}
+
+	for _, tc := range testCases {
+		sv, err := semverparams.BuildInfoSemver(&tc.bi)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "semver",
+				sv.String(), tc.expSV)
+		}
+	}
+}
+
+func TestProgSemver(t *testing.T) {
+	defer func(v string) { semverparams.ProgVersion = v }(
+		semverparams.ProgVersion)
+
+	semverparams.ProgVersion = "2.3.4-rc.1"
+
+	sv, err := semverparams.ProgSemver()
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffString(t, "ProgVersion set", "semver",
+		sv.String(), "v2.3.4-rc.1")
+
+	panicked, panicVal := testhelper.PanicSafe(func() {
+		_ = paramset.NewNoHelpNoExitNoErrRpt(semverparams.AddVersionParam)
+	})
+	testhelper.ReportUnexpectedPanic(t, "AddVersionParam",
+		panicked, panicVal, nil)
+}
+
+// versionHelperEnv is the name of the environment variable which tells
+// TestVersionParamHelper how to set the program version
+const versionHelperEnv = "SEMVERPARAMS_TEST_VERSION_PARAM"
+
+// TestVersionParamHelper is not a real test, it is run in a sub-process by
+// TestVersionParam as the version parameter exits the program
+func TestVersionParamHelper(t *testing.T) {
+	progVersion, ok := os.LookupEnv(versionHelperEnv)
+	if !ok {
+		t.Skip("only run by TestVersionParam")
+	}
+
+	semverparams.ProgVersion = progVersion
+
+	ps := paramset.NewNoHelpNoExitNoErrRpt(semverparams.AddVersionParam)
+	ps.Parse([]string{"-version"})
+
+	t.Fatal("the version parameter did not exit")
+}
+
+func TestVersionParam(t *testing.T) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Fatal("the test program has no build information")
+	}
+
+	biSV, err := semverparams.BuildInfoSemver(bi)
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		progVersion string
+		expOut      string
+	}{
+		{
+			ID:          testhelper.MkID("set by ldflags"),
+			progVersion: "2.3.4-rc.1",
+			expOut:      "v2.3.4-rc.1\n",
+		},
+		{
+			ID:     testhelper.MkID("from the build information"),
+			expOut: biSV.String() + "\n",
+		},
+		{
+			ID:          testhelper.MkID("bad ldflags version"),
+			progVersion: "1.2",
+			expOut: "the program version is unknown:" +
+				" bad semantic version ID" +
+				" - it cannot be split into major/minor/patch parts\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		cmd := exec.Command(os.Args[0], //nolint:gosec
+			"-test.run=^TestVersionParamHelper$")
+		cmd.Env = append(os.Environ(), versionHelperEnv+"="+tc.progVersion)
+
+		out, err := cmd.Output()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Errorf("\t: unexpected error: %v\n%s", err, out)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "output", string(out), tc.expOut)
+	}
+}
@@ -0,0 +1,343 @@
+package semverparams
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/location.mod/location"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/psetter"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// SourceKind records the kind of place where a value was set
+type SourceKind int
+
+// These are the kinds of place where a value can be set
+const (
+	// SourceDefault means the value has not been set by a parameter
+	SourceDefault SourceKind = iota
+	SourceCommandLine
+	SourceConfigFile
+	SourceEnvironment
+	SourceOther
+)
+
+// String returns a description of the SourceKind
+func (sk SourceKind) String() string {
+	switch sk {
+	case SourceDefault:
+		return "default"
+	case SourceCommandLine:
+		return param.SrcCommandLine
+	case SourceConfigFile:
+		return param.SrcConfigFilePfx
+	case SourceEnvironment:
+		return param.SrcEnvironment
+	case SourceOther:
+		return "other"
+	}
+
+	return fmt.Sprintf("unknown source kind (%d)", int(sk))
+}
+
+// ValSource records a place where a value was set
+type ValSource struct {
+	Kind SourceKind
+	// Param is the name of the parameter which set the value
+	Param string
+	// Value is the parameter value as given
+	Value string
+	// Source is the name of the source of the parameter: the config file
+	// name, or the name given to the command line arguments. For the
+	// environment it is empty.
+	Source string
+	// Idx is the line number in a config file or the position in the
+	// command line arguments
+	Idx int64
+	// Desc is the full description of the place where the value was set
+	Desc string
+}
+
+// String returns a description of the place where the value was set
+func (vs ValSource) String() string {
+	if vs.Kind == SourceDefault {
+		return "not set, the default value is used"
+	}
+
+	return vs.Desc
+}
+
+// sourceKindFor returns the SourceKind for the location
+func sourceKindFor(loc location.L) SourceKind {
+	note := loc.Note()
+
+	switch {
+	case note == param.SrcCommandLine:
+		return SourceCommandLine
+	case note == param.SrcEnvironment:
+		return SourceEnvironment
+	case strings.Contains(note, param.SrcConfigFilePfx):
+		return SourceConfigFile
+	}
+
+	return SourceOther
+}
+
+// These are the names of the values whose sources are recorded
+const (
+	srcValSemVer    = "semver"
+	srcValPreRelIDs = "pre-rel-IDs"
+	srcValBuildIDs  = "build-IDs"
+	srcValChannel   = "channel"
+)
+
+// recordSource returns an ActionFunc which records where the named value
+// was set
+func (pp *psetParams) recordSource(valName string) param.ActionFunc {
+	return func(loc location.L, p *param.BaseParam, paramVals []string) error {
+		vs := ValSource{
+			Kind:   sourceKindFor(loc),
+			Param:  p.Name(),
+			Source: loc.Source(),
+			Idx:    loc.Idx(),
+			Desc:   loc.String(),
+		}
+
+		if len(paramVals) > 1 {
+			vs.Value = paramVals[len(paramVals)-1]
+		}
+
+		if pp.sources == nil {
+			pp.sources = map[string][]ValSource{}
+		}
+
+		pp.sources[valName] = append(pp.sources[valName], vs)
+
+		return nil
+	}
+}
+
+// sourcesOf returns every place where the named value was set by the
+// parameters added to the PSet. It is empty if no parameters have been
+// added to the PSet.
+func (pp *psetParams) sourcesOf(valName string) []ValSource {
+	if pp == nil {
+		return nil
+	}
+
+	return slices.Clone(pp.sources[valName])
+}
+
+// sourcesOf returns every place where the named value was set by the
+// parameters added to any of the PSets, in the order in which the PSets
+// were added
+func (svv SemverVals) sourcesOf(valName string) []ValSource {
+	var srcs []ValSource
+
+	for _, pp := range svv.params {
+		srcs = append(srcs, pp.sources[valName]...)
+	}
+
+	return srcs
+}
+
+// lastSource returns the last of the sources or a default ValSource if
+// there are none
+func lastSource(srcs []ValSource) ValSource {
+	if len(srcs) == 0 {
+		return ValSource{Kind: SourceDefault}
+	}
+
+	return srcs[len(srcs)-1]
+}
+
+// SemVerSources returns every place where the SemVer was set by a
+// parameter, in the order they were applied. It is empty if the SemVer has
+// not been set. If the parameters have been added to more than one PSet the
+// places from all of them are given, see SemVerSourcesIn.
+func (svv SemverVals) SemVerSources() []ValSource {
+	return svv.sourcesOf(srcValSemVer)
+}
+
+// SemVerSourcesIn returns every place where the SemVer was set by the
+// parameters added to the given PSet
+func (svv SemverVals) SemVerSourcesIn(ps *param.PSet) []ValSource {
+	return svv.findParams(ps).sourcesOf(srcValSemVer)
+}
+
+// SemVerSource returns the place where the current SemVer value was set
+func (svv SemverVals) SemVerSource() ValSource {
+	return lastSource(svv.SemVerSources())
+}
+
+// PreRelIDsSources returns every place where the PreRelIDs were set by a
+// parameter, in the order they were applied. It is empty if the PreRelIDs
+// have not been set. If the parameters have been added to more than one
+// PSet the places from all of them are given, see PreRelIDsSourcesIn.
+func (svv SemverVals) PreRelIDsSources() []ValSource {
+	return svv.sourcesOf(srcValPreRelIDs)
+}
+
+// PreRelIDsSourcesIn returns every place where the PreRelIDs were set by
+// the parameters added to the given PSet
+func (svv SemverVals) PreRelIDsSourcesIn(ps *param.PSet) []ValSource {
+	return svv.findParams(ps).sourcesOf(srcValPreRelIDs)
+}
+
+// PreRelIDsSource returns the place where the current PreRelIDs value was
+// set
+func (svv SemverVals) PreRelIDsSource() ValSource {
+	return lastSource(svv.PreRelIDsSources())
+}
+
+// BuildIDsSources returns every place where the BuildIDs were set by a
+// parameter, in the order they were applied. It is empty if the BuildIDs
+// have not been set. If the parameters have been added to more than one
+// PSet the places from all of them are given, see BuildIDsSourcesIn.
+func (svv SemverVals) BuildIDsSources() []ValSource {
+	return svv.sourcesOf(srcValBuildIDs)
+}
+
+// BuildIDsSourcesIn returns every place where the BuildIDs were set by the
+// parameters added to the given PSet
+func (svv SemverVals) BuildIDsSourcesIn(ps *param.PSet) []ValSource {
+	return svv.findParams(ps).sourcesOf(srcValBuildIDs)
+}
+
+// BuildIDsSource returns the place where the current BuildIDs value was
+// set
+func (svv SemverVals) BuildIDsSource() ValSource {
+	return lastSource(svv.BuildIDsSources())
+}
+
+// ChannelSources returns every place where the Channel was set by a
+// parameter, in the order they were applied. It is empty if the Channel has
+// not been set. If the parameters have been added to more than one PSet the
+// places from all of them are given, see ChannelSourcesIn.
+func (svv SemverVals) ChannelSources() []ValSource {
+	return svv.sourcesOf(srcValChannel)
+}
+
+// ChannelSourcesIn returns every place where the Channel was set by the
+// parameters added to the given PSet
+func (svv SemverVals) ChannelSourcesIn(ps *param.PSet) []ValSource {
+	return svv.findParams(ps).sourcesOf(srcValChannel)
+}
+
+// ChannelSource returns the place where the current Channel value was set
+func (svv SemverVals) ChannelSource() ValSource {
+	return lastSource(svv.ChannelSources())
+}
+
+// sourceReport holds a value and the places where it was set, for
+// reporting by writeSources
+type sourceReport struct {
+	name string
+	val  string
+	srcs []ValSource
+}
+
+// WriteSources writes a report of the values and where they were set to
+// the writer. The Channel is only reported if the channel parameter has
+// been added. If the parameters have been added to more than one PSet the
+// places from all of them are given, see WriteSourcesIn.
+func (svv SemverVals) WriteSources(w io.Writer) {
+	reports := []sourceReport{
+		{srcValSemVer, svv.SemVer.String(), svv.SemVerSources()},
+		{srcValPreRelIDs, strings.Join(svv.PreRelIDs, "."),
+			svv.PreRelIDsSources()},
+		{srcValBuildIDs, strings.Join(svv.BuildIDs, "."),
+			svv.BuildIDsSources()},
+	}
+
+	if slices.ContainsFunc(svv.params, (*psetParams).hasChannelParam) {
+		reports = append(reports,
+			sourceReport{srcValChannel, svv.Channel, svv.ChannelSources()})
+	}
+
+	writeSources(w, reports)
+}
+
+// WriteSourcesIn writes a report of the values and where they were set by
+// the parameters added to the given PSet to the writer. The Channel is only
+// reported if the channel parameter has been added to the PSet.
+func (svv SemverVals) WriteSourcesIn(w io.Writer, ps *param.PSet) {
+	reports := []sourceReport{
+		{srcValSemVer, svv.SemVer.String(), svv.SemVerSourcesIn(ps)},
+		{srcValPreRelIDs, strings.Join(svv.PreRelIDs, "."),
+			svv.PreRelIDsSourcesIn(ps)},
+		{srcValBuildIDs, strings.Join(svv.BuildIDs, "."),
+			svv.BuildIDsSourcesIn(ps)},
+	}
+
+	if svv.findParams(ps).hasChannelParam() {
+		reports = append(reports,
+			sourceReport{srcValChannel, svv.Channel, svv.ChannelSourcesIn(ps)})
+	}
+
+	writeSources(w, reports)
+}
+
+// hasChannelParam returns true if the channel parameter has been added to
+// the PSet
+func (pp *psetParams) hasChannelParam() bool {
+	return pp != nil && pp.channel != nil
+}
+
+// writeSources writes the values and the places where they were set
+func writeSources(w io.Writer, reports []sourceReport) {
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s: %s\n", r.name, r.val)
+
+		if len(r.srcs) == 0 {
+			fmt.Fprintf(w, "\t%s\n", ValSource{Kind: SourceDefault})
+			continue
+		}
+
+		for _, vs := range r.srcs {
+			fmt.Fprintf(w, "\t%s\n", vs)
+		}
+	}
+}
+
+// AddShowSourceParam returns a function that will add a parameter to the
+// passed PSet which, if given, causes a report of the values and where
+// they were set by the parameters in that PSet to be written to the StdW
+// once all the parameters have been parsed.
+func (svv *SemverVals) AddShowSourceParam() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svv.Prefix != "" {
+			prefix = svv.Prefix + "-"
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		var showSource bool
+
+		ps.Add(prefix+"show-semver-source",
+			psetter.Bool{Value: &showSource},
+			"show where the "+semver.Name+
+				", any pre-release and build IDs and the release"+
+				" channel were set",
+			param.GroupName(groupName),
+			param.Attrs(param.CommandLineOnly|param.DontShowInStdUsage),
+		)
+
+		ps.AddFinalCheck(func() error {
+			if showSource {
+				svv.WriteSourcesIn(svv.stdW(), ps)
+			}
+
+			return nil
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,212 @@
+package semverparams_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/filecheck.mod/filecheck"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSources(t *testing.T) {
+	cfgFile := filepath.Join(t.TempDir(), "params.cfg")
+
+	err := os.WriteFile(cfgFile,
+		[]byte("# a comment\nsemver = v0.9.0\nbuild-IDs = cfg.1\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SVSRCTEST_pre_rel_IDs", "rc.2")
+
+	var stdout strings.Builder
+
+	svv := semverparams.SemverVals{StdW: &stdout}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+		svv.AddIDParams(nil),
+		svv.AddShowSourceParam(),
+	)
+	ps.SetConfigFile(cfgFile, filecheck.MustExist)
+	ps.AddEnvPrefix("SVSRCTEST_")
+
+	ps.Parse([]string{"-semver", "v1.2.3", "-show-semver-source"})
+
+	if errMap := ps.Errors(); len(errMap) != 0 {
+		t.Fatal("unexpected errors:", errMap)
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		srcs     []semverparams.ValSource
+		expKinds []semverparams.SourceKind
+		expVals  []string
+		expIdxs  []int64
+	}{
+		{
+			ID:   testhelper.MkID("semver"),
+			srcs: svv.SemVerSources(),
+			expKinds: []semverparams.SourceKind{
+				semverparams.SourceConfigFile,
+				semverparams.SourceCommandLine,
+			},
+			expVals: []string{"v0.9.0", "v1.2.3"},
+			expIdxs: []int64{2, 2},
+		},
+		{
+			ID:       testhelper.MkID("pre-release IDs"),
+			srcs:     svv.PreRelIDsSources(),
+			expKinds: []semverparams.SourceKind{semverparams.SourceEnvironment},
+			expVals:  []string{"rc.2"},
+			expIdxs:  []int64{0},
+		},
+		{
+			ID:       testhelper.MkID("build IDs"),
+			srcs:     svv.BuildIDsSources(),
+			expKinds: []semverparams.SourceKind{semverparams.SourceConfigFile},
+			expVals:  []string{"cfg.1"},
+			expIdxs:  []int64{3},
+		},
+	}
+
+	for _, tc := range testCases {
+		if testhelper.DiffInt(t, tc.IDStr(), "source count",
+			len(tc.srcs), len(tc.expKinds)) {
+			continue
+		}
+
+		for i, vs := range tc.srcs {
+			testhelper.DiffString(t, tc.IDStr(), "kind",
+				vs.Kind.String(), tc.expKinds[i].String())
+			testhelper.DiffString(t, tc.IDStr(), "value",
+				vs.Value, tc.expVals[i])
+			testhelper.DiffInt(t, tc.IDStr(), "index",
+				int(vs.Idx), int(tc.expIdxs[i]))
+		}
+	}
+
+	testhelper.DiffString(t, "effective source", "param",
+		svv.SemVerSource().Param, "semver")
+	testhelper.DiffString(t, "effective source", "kind",
+		svv.SemVerSource().Kind.String(),
+		semverparams.SourceCommandLine.String())
+	testhelper.DiffString(t, "effective source", "config file",
+		svv.BuildIDsSource().Source, cfgFile)
+
+	expOut := "semver: v1.2.3\n" +
+		"\t" + svv.SemVerSources()[0].Desc + "\n" +
+		"\t" + svv.SemVerSources()[1].Desc + "\n" +
+		"pre-rel-IDs: rc.2\n" +
+		"\t" + svv.PreRelIDsSource().Desc + "\n" +
+		"build-IDs: cfg.1\n" +
+		"\t" + svv.BuildIDsSource().Desc + "\n"
+	testhelper.DiffString(t, "show-semver-source", "output",
+		stdout.String(), expOut)
+
+	if !strings.Contains(svv.SemVerSource().Desc, `"-semver" "v1.2.3"`) {
+		t.Error("the command line source does not show the parameter:",
+			svv.SemVerSource().Desc)
+	}
+}
+
+func TestSourcesDefault(t *testing.T) {
+	svv := semverparams.SemverVals{}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+	)
+	ps.Parse([]string{})
+
+	testhelper.DiffInt(t, "default", "source count",
+		len(svv.SemVerSources()), 0)
+	testhelper.DiffString(t, "default", "kind",
+		svv.SemVerSource().Kind.String(),
+		semverparams.SourceDefault.String())
+
+	var b strings.Builder
+
+	svv.WriteSources(&b)
+	testhelper.DiffString(t, "default", "report", b.String(),
+		"semver: \n"+
+			"\tnot set, the default value is used\n"+
+			"pre-rel-IDs: \n"+
+			"\tnot set, the default value is used\n"+
+			"build-IDs: \n"+
+			"\tnot set, the default value is used\n")
+}
+
+func TestSourcesPerPSet(t *testing.T) {
+	var stdout strings.Builder
+
+	svv := semverparams.SemverVals{StdW: &stdout}
+	ps1 := paramset.NewNoHelpNoExitNoErrRpt(
+		svv.AddSemverParam(nil),
+		svv.AddShowSourceParam(),
+	)
+	ps2 := paramset.NewNoHelpNoExitNoErrRpt(
+		svv.AddSemverParam(nil),
+		svv.AddShowSourceParam(),
+	)
+
+	ps1.Parse([]string{"-semver", "v1.2.3"})
+	ps2.Parse([]string{"-semver", "v2.0.0", "-show-semver-source"})
+
+	testhelper.DiffInt(t, "all PSets", "source count",
+		len(svv.SemVerSources()), 2)
+	testhelper.DiffInt(t, "first PSet", "source count",
+		len(svv.SemVerSourcesIn(ps1)), 1)
+
+	srcs := svv.SemVerSourcesIn(ps2)
+	if !testhelper.DiffInt(t, "second PSet", "source count", len(srcs), 1) {
+		testhelper.DiffString(t, "second PSet", "value",
+			srcs[0].Value, "v2.0.0")
+	}
+
+	testhelper.DiffString(t, "show-semver-source", "output", stdout.String(),
+		"semver: v2.0.0\n"+
+			"\t"+svv.SemVerSource().Desc+"\n"+
+			"pre-rel-IDs: \n"+
+			"\tnot set, the default value is used\n"+
+			"build-IDs: \n"+
+			"\tnot set, the default value is used\n")
+}
+
+func TestChannelSources(t *testing.T) {
+	var stdout strings.Builder
+
+	svv := semverparams.SemverVals{StdW: &stdout}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		svv.AddSemverParam(nil),
+		svv.AddChannelParam(nil),
+		svv.AddShowSourceParam(),
+	)
+	ps.Parse([]string{
+		"-semver", "v1.2.0-beta.2", "-channel", "rc", "-show-semver-source",
+	})
+
+	if errMap := ps.Errors(); len(errMap) != 0 {
+		t.Fatal("unexpected errors:", errMap)
+	}
+
+	srcs := svv.ChannelSourcesIn(ps)
+	if !testhelper.DiffInt(t, "channel", "source count", len(srcs), 1) {
+		testhelper.DiffString(t, "channel", "value", srcs[0].Value, "rc")
+		testhelper.DiffString(t, "channel", "kind", srcs[0].Kind.String(),
+			semverparams.SourceCommandLine.String())
+	}
+
+	testhelper.DiffString(t, "show-semver-source", "output", stdout.String(),
+		"semver: v1.2.0-rc.1\n"+
+			"\t"+svv.SemVerSource().Desc+"\n"+
+			"pre-rel-IDs: \n"+
+			"\tnot set, the default value is used\n"+
+			"build-IDs: \n"+
+			"\tnot set, the default value is used\n"+
+			"channel: rc\n"+
+			"\t"+svv.ChannelSource().Desc+"\n")
+}
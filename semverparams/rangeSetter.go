@@ -0,0 +1,58 @@
+package semverparams
+
+import (
+	"github.com/nickwells/param.mod/v7/psetter"
+)
+
+// RangeSetter is a parameter setter which will set a range of semantic
+// version numbers. It satisfies the param.Setter interface and so can be
+// used when specifying a command line argument using the param package.
+type RangeSetter struct {
+	psetter.ValueReqMandatory
+
+	Value **Range
+}
+
+// SetWithVal parses the parameter value as a range expression. It returns
+// an error if the expression is invalid. Only if the expression is valid is
+// the Value set.
+func (rs RangeSetter) SetWithVal(_ string, paramVal string) error {
+	r, err := ParseRange(paramVal)
+	if err != nil {
+		return err
+	}
+
+	*rs.Value = r
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (rs RangeSetter) AllowedValues() string {
+	return rangeAllowedValues
+}
+
+// rangeAllowedValues describes a range expression
+const rangeAllowedValues = "a range of semantic version numbers" +
+	" given as one or more alternatives separated by '" + rangeAltSep + "'," +
+	" each alternative is a space-separated list of comparisons" +
+	" all of which must be satisfied." +
+	" A comparison is an optional operator (=, !=, >, >=, <, <=, ^ or ~)" +
+	" followed by a possibly partial version with an optional leading 'v'." +
+	" For instance, '>=v2.6 <v3' or '^v1.4.2 || ^v2'."
+
+// CurrentValue returns the current setting of the parameter value
+func (rs RangeSetter) CurrentValue() string {
+	if *rs.Value == nil {
+		return ""
+	}
+
+	return (*rs.Value).String()
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (rs RangeSetter) CheckSetter(name string) {
+	if rs.Value == nil {
+		panic(name + ": RangeSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,187 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/internal/gitrepo"
+)
+
+// tagRefPfx is the prefix of the full reference names of git tags
+const tagRefPfx = "refs/tags/"
+
+// ErrNoTags is returned if no matching version tags can be found
+var ErrNoTags = errors.New("no version tags found")
+
+// tagPrefixDir returns the tag prefix as a directory name. Any leading or
+// trailing '/' is removed and, if the result is not empty, a single '/' is
+// added to the end.
+func tagPrefixDir(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+
+	return prefix + "/"
+}
+
+// TagName returns the name of the tag for the semantic version number. If
+// the prefix is not empty the tag name is the prefix and the version number
+// separated by a '/' (for instance "services/billing/v1.4.0"). Otherwise it
+// is just the version number.
+func TagName(prefix string, sv *semver.SV) string {
+	return tagPrefixDir(prefix) + sv.String()
+}
+
+// ParseTag converts a tag name into a semantic version number. The tag
+// name may be given with or without the leading "refs/tags/". It must
+// start with the prefix (as a directory name, see TagName) and the rest of
+// the name must be a valid semantic version number. An error is returned
+// if not.
+func ParseTag(prefix, tag string) (*semver.SV, error) {
+	name := strings.TrimPrefix(tag, tagRefPfx)
+
+	rest, ok := strings.CutPrefix(name, tagPrefixDir(prefix))
+	if !ok {
+		return nil, fmt.Errorf("tag %q does not start with %q",
+			name, tagPrefixDir(prefix))
+	}
+
+	sv, err := semver.ParseSV(rest)
+	if err != nil {
+		return nil, fmt.Errorf("tag %q: %w", name, err)
+	}
+
+	return sv, nil
+}
+
+// RepoTag records a version tag from a git repository
+type RepoTag struct {
+	// Name is the tag name without the leading "refs/tags/"
+	Name string
+	// SemVer is the version number given by the tag, without the prefix
+	SemVer semver.SV
+	// Commit is the name of the tagged commit
+	Commit string
+	// Time is the commit time of the tagged commit, it is the zero time if
+	// the tagged object is not a commit
+	Time time.Time
+}
+
+// UnparsedTag records a tag whose name, after the prefix, is not a semantic
+// version number
+type UnparsedTag struct {
+	// Name is the tag name without the leading "refs/tags/"
+	Name string
+	// Err gives the reason the name could not be parsed
+	Err error
+}
+
+// ReadRepoTags returns the version tags with the given prefix in the git
+// repository containing the given directory. Tags whose names, after the
+// prefix, are not semantic version numbers are ignored, so a prefix of
+// "services" will not see the tags of "services/billing". The tags are
+// returned in increasing order of version number.
+func ReadRepoTags(repoDir, prefix string) ([]RepoTag, error) {
+	tags, _, err := ReadAllRepoTags(repoDir, prefix)
+
+	return tags, err
+}
+
+// ReadAllRepoTags is like ReadRepoTags but it also returns the tags with
+// the given prefix whose names are not semantic version numbers, in
+// alphabetical order. Tags whose names, after the prefix, contain a '/'
+// are taken to belong to some other prefix and are not returned.
+func ReadAllRepoTags(repoDir, prefix string,
+) ([]RepoTag, []UnparsedTag, error) {
+	r, err := gitrepo.Find(repoDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refs, err := r.Refs(tagRefPfx + tagPrefixDir(prefix))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags := make([]RepoTag, 0, len(refs))
+
+	var unparsed []UnparsedTag
+
+	for ref, h := range refs {
+		name := strings.TrimPrefix(ref, tagRefPfx)
+
+		sv, err := ParseTag(prefix, ref)
+		if err != nil {
+			rest := strings.TrimPrefix(name, tagPrefixDir(prefix))
+			if !strings.Contains(rest, "/") {
+				unparsed = append(unparsed, UnparsedTag{Name: name, Err: err})
+			}
+
+			continue
+		}
+
+		commit, err := r.Peel(h)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tag %q: %w", name, err)
+		}
+
+		tag := RepoTag{
+			Name:   name,
+			SemVer: *sv,
+			Commit: commit.String(),
+		}
+
+		if c, err := r.ReadCommit(commit); err == nil {
+			tag.Time = c.Time
+		}
+
+		tags = append(tags, tag)
+	}
+
+	slices.SortFunc(tags, func(a, b RepoTag) int {
+		if c := svCompare(&a.SemVer, &b.SemVer); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	slices.SortFunc(unparsed, func(a, b UnparsedTag) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return tags, unparsed, nil
+}
+
+// LatestRepoTag returns the version tag with the given prefix having the
+// highest version number. It returns ErrNoTags if there are none.
+func LatestRepoTag(repoDir, prefix string) (RepoTag, error) {
+	tags, err := ReadRepoTags(repoDir, prefix)
+	if err != nil {
+		return RepoTag{}, err
+	}
+
+	if len(tags) == 0 {
+		return RepoTag{}, fmt.Errorf("%w with prefix %q",
+			ErrNoTags, tagPrefixDir(prefix))
+	}
+
+	return tags[len(tags)-1], nil
+}
+
+// TagName returns the name of the tag for the SemVer, including the
+// TagPrefix
+func (svv SemverVals) TagName() string {
+	return TagName(svv.TagPrefix, &svv.SemVer)
+}
+
+// RepoTags returns the version tags for the TagPrefix in the git
+// repository containing the given directory. See ReadRepoTags.
+func (svv SemverVals) RepoTags(repoDir string) ([]RepoTag, error) {
+	return ReadRepoTags(repoDir, svv.TagPrefix)
+}
@@ -0,0 +1,225 @@
+package semverparams_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestTagName(t *testing.T) {
+	sv := semver.NewSVOrPanic(1, 4, 0, []string{"rc", "1"}, nil)
+
+	testCases := []struct {
+		testhelper.ID
+		prefix string
+		expTag string
+	}{
+		{
+			ID:     testhelper.MkID("no prefix"),
+			expTag: "v1.4.0-rc.1",
+		},
+		{
+			ID:     testhelper.MkID("prefix"),
+			prefix: "services/billing",
+			expTag: "services/billing/v1.4.0-rc.1",
+		},
+		{
+			ID:     testhelper.MkID("prefix with slashes"),
+			prefix: "/services/billing/",
+			expTag: "services/billing/v1.4.0-rc.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		tag := semverparams.TagName(tc.prefix, sv)
+		testhelper.DiffString(t, tc.IDStr(), "tag name", tag, tc.expTag)
+
+		parsed, err := semverparams.ParseTag(tc.prefix, "refs/tags/"+tag)
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Error("\t: unexpected error:", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "parsed tag",
+			parsed.String(), sv.String())
+	}
+}
+
+func TestParseTagErrs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		prefix string
+		tag    string
+	}{
+		{
+			ID: testhelper.MkID("other prefix"),
+			ExpErr: testhelper.MkExpErr(
+				`tag "services/auth/v1.0.0"` +
+					` does not start with "services/billing/"`),
+			prefix: "services/billing",
+			tag:    "services/auth/v1.0.0",
+		},
+		{
+			ID: testhelper.MkID("deeper tag"),
+			ExpErr: testhelper.MkExpErr(`tag "services/billing/v1.0.0": `,
+				"bad semantic version ID"),
+			prefix: "services",
+			tag:    "services/billing/v1.0.0",
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := semverparams.ParseTag(tc.prefix, tc.tag)
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
+
+func TestSetterTagPrefix(t *testing.T) {
+	svv := semverparams.SemverVals{TagPrefix: "services/billing"}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(nil),
+	)
+
+	ps.Parse([]string{"-semver", "services/billing/v1.4.0"})
+
+	if errMap := ps.Errors(); len(errMap) != 0 {
+		t.Fatal("unexpected errors:", errMap)
+	}
+
+	testhelper.DiffString(t, "tag prefix", "semver",
+		svv.SemVer.String(), "v1.4.0")
+	testhelper.DiffString(t, "tag prefix", "tag name",
+		svv.TagName(), "services/billing/v1.4.0")
+}
+
+func TestReadRepoTags(t *testing.T) {
+	dir := t.TempDir()
+
+	runGit(t, dir, "init", "-q", "-b", "main")
+
+	err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("text\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runGit(t, dir, "add", "f.txt")
+	runGit(t, dir, "commit", "-q", "-m", "first")
+
+	for _, tag := range []string{
+		"v2.0.0",
+		"services/billing/v1.10.0",
+		"services/billing/v1.4.0",
+		"services/billing/v1.9.0-rc.1",
+		"services/billing/not-a-version",
+		"services/auth/v3.0.0",
+	} {
+		runGit(t, dir, "tag", tag)
+	}
+
+	runGit(t, dir, "tag", "-a", "-m", "annotated",
+		"services/billing/v1.11.0-rc.1")
+	runGit(t, dir, "pack-refs", "--all")
+	runGit(t, dir, "tag", "services/billing/v1.2.0")
+
+	testCases := []struct {
+		testhelper.ID
+		prefix  string
+		expTags []string
+	}{
+		{
+			ID:      testhelper.MkID("no prefix"),
+			expTags: []string{"v2.0.0"},
+		},
+		{
+			ID:     testhelper.MkID("billing"),
+			prefix: "services/billing",
+			expTags: []string{
+				"services/billing/v1.2.0",
+				"services/billing/v1.4.0",
+				"services/billing/v1.9.0-rc.1",
+				"services/billing/v1.10.0",
+				"services/billing/v1.11.0-rc.1",
+			},
+		},
+		{
+			ID:     testhelper.MkID("services"),
+			prefix: "services",
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{TagPrefix: tc.prefix}
+
+		tags, err := svv.RepoTags(dir)
+		if err != nil {
+			t.Fatal(tc.IDStr(), "unexpected error:", err)
+		}
+
+		var names []string
+
+		for _, tag := range tags {
+			names = append(names, tag.Name)
+
+			if tag.Name != semverparams.TagName(tc.prefix, &tag.SemVer) {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: tag %q has the wrong version: %s",
+					tag.Name, tag.SemVer.String())
+			}
+
+			if len(tag.Commit) != 40 {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: tag %q has a bad commit: %q",
+					tag.Name, tag.Commit)
+			}
+
+			if tag.Time.IsZero() {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: tag %q has no commit time", tag.Name)
+			}
+		}
+
+		testhelper.DiffStringSlice(t, tc.IDStr(), "tags", names, tc.expTags)
+	}
+
+	_, unparsed, err := semverparams.ReadAllRepoTags(dir, "services/billing")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	if !testhelper.DiffInt(t, "ReadAllRepoTags", "unparsed tag count",
+		len(unparsed), 1) {
+		testhelper.DiffString(t, "ReadAllRepoTags", "unparsed tag",
+			unparsed[0].Name, "services/billing/not-a-version")
+	}
+
+	_, unparsed, err = semverparams.ReadAllRepoTags(dir, "")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffInt(t, "ReadAllRepoTags, no prefix", "unparsed tag count",
+		len(unparsed), 0)
+
+	latest, err := semverparams.LatestRepoTag(dir, "services/billing")
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffString(t, "LatestRepoTag", "tag",
+		latest.Name, "services/billing/v1.11.0-rc.1")
+
+	_, err = semverparams.LatestRepoTag(dir, "services")
+	if !errors.Is(err, semverparams.ErrNoTags) {
+		t.Error("LatestRepoTag: expected ErrNoTags, got:", err)
+	}
+}
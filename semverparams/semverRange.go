@@ -0,0 +1,317 @@
+package semverparams
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// Range represents a set of semantic version numbers given by a range
+// expression. A range expression is one or more alternatives separated by
+// '||'; a version is in the range if it satisfies any of the
+// alternatives. Each alternative is a white-space separated list of
+// comparisons all of which must be satisfied. A comparison is an optional
+// operator followed by a version. The operators are:
+//
+//	=   (the default) the version must equal the given version
+//	!=  the version must not equal the given version
+//	>   the version must be greater than the given version
+//	>=  the version must be greater than or equal to the given version
+//	<   the version must be less than the given version
+//	<=  the version must be less than or equal to the given version
+//	^   the version must be compatible with the given version, it must be
+//	    no less than the given version and have the same first non-zero part
+//	~   the version must be no less than the given version and have the
+//	    same major and minor parts
+//
+// The leading 'v' of the version is optional and the version may be
+// partial, giving just the major or the major and minor parts (the missing
+// parts may also be given as 'x' or '*'). A partial version stands for all
+// the versions starting with the given parts so, for instance, 'v1.2' is
+// equivalent to '>=v1.2.0 <v1.3.0-0' and '>v1.2' is equivalent to
+// '>=v1.3.0'. A version of just '*' matches every version.
+//
+// Versions are compared using the precedence rules of the Semantic
+// Versioning spec so build IDs are ignored. Upper bounds implied by partial
+// versions and by the '^' and '~' operators exclude the pre-releases of the
+// bounding version.
+type Range struct {
+	text string
+	alts [][]rangeCmp
+}
+
+// rangeCmp represents a single comparison. A version satisfies the
+// comparison if it lies between the lower and upper bounds (a nil bound is
+// unbounded). If negate is set the result is inverted.
+type rangeCmp struct {
+	lo, hi         *semver.SV
+	loIncl, hiIncl bool
+	negate         bool
+}
+
+// svPartCount is the number of numeric parts in a semantic version number
+const svPartCount = 3
+
+// rangeAltSep separates the alternatives in a range expression
+const rangeAltSep = "||"
+
+// rangeOps lists the operators in the order in which they should be
+// matched (longest first)
+var rangeOps = []string{"!=", ">=", "<=", "=", ">", "<", "^", "~"}
+
+// svCompare returns -1, 0 or 1 as a is less than, equal to or greater than b
+// according to the precedence rules of the Semantic Versioning spec
+func svCompare(a, b *semver.SV) int {
+	if semver.Less(a, b) {
+		return -1
+	}
+
+	if semver.Less(b, a) {
+		return 1
+	}
+
+	return 0
+}
+
+// contains returns true if the version satisfies the comparison
+func (rc rangeCmp) contains(sv *semver.SV) bool {
+	in := true
+
+	if rc.lo != nil {
+		c := svCompare(sv, rc.lo)
+		in = c > 0 || (c == 0 && rc.loIncl)
+	}
+
+	if in && rc.hi != nil {
+		c := svCompare(sv, rc.hi)
+		in = c < 0 || (c == 0 && rc.hiIncl)
+	}
+
+	return in != rc.negate
+}
+
+// ParseRange parses the range expression and returns the corresponding
+// Range or a non-nil error if the expression is invalid.
+func ParseRange(s string) (*Range, error) {
+	r := &Range{text: strings.TrimSpace(s)}
+
+	if r.text == "" {
+		return nil, errors.New("the range expression is empty")
+	}
+
+	for alt := range strings.SplitSeq(r.text, rangeAltSep) {
+		fields := strings.Fields(alt)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("bad range %q: an alternative is empty",
+				r.text)
+		}
+
+		cmps := make([]rangeCmp, 0, len(fields))
+
+		for _, f := range fields {
+			rc, err := parseRangeCmp(f)
+			if err != nil {
+				return nil, fmt.Errorf("bad range %q: %w", r.text, err)
+			}
+
+			cmps = append(cmps, rc)
+		}
+
+		r.alts = append(r.alts, cmps)
+	}
+
+	return r, nil
+}
+
+// Contains returns true if the version is in the range
+func (r Range) Contains(sv *semver.SV) bool {
+	for _, alt := range r.alts {
+		in := true
+
+		for _, rc := range alt {
+			if !rc.contains(sv) {
+				in = false
+				break
+			}
+		}
+
+		if in {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the range expression
+func (r Range) String() string {
+	return r.text
+}
+
+// partialSV holds a possibly partial version. The parts slice holds the
+// major, minor and patch parts that were given, the sv is the version with
+// any missing parts set to zero.
+type partialSV struct {
+	parts []int
+	sv    *semver.SV
+}
+
+// isFull returns true if all the parts of the version were given
+func (p partialSV) isFull() bool {
+	return len(p.parts) == svPartCount
+}
+
+// bump returns the lowest version greater than all the versions
+// starting with the first n parts of p. It returns nil if n is zero.
+func (p partialSV) bump(n int) *semver.SV {
+	if n == 0 {
+		return nil
+	}
+
+	parts := make([]int, svPartCount)
+	copy(parts, p.parts[:n])
+	parts[n-1]++
+
+	return semver.NewSVOrPanic(parts[0], parts[1], parts[2],
+		[]string{"0"}, nil)
+}
+
+// withLowestPreRel returns the version with the lowest possible pre-release
+// ID; no other version with the same major, minor and patch parts is lower.
+func (p partialSV) withLowestPreRel() *semver.SV {
+	return semver.NewSVOrPanic(p.sv.Major(), p.sv.Minor(), p.sv.Patch(),
+		[]string{"0"}, nil)
+}
+
+// parsePartialSV parses a possibly partial version
+func parsePartialSV(s string) (partialSV, error) {
+	v := strings.TrimPrefix(s, "v")
+
+	if !strings.ContainsAny(v, "-+") {
+		p := partialSV{}
+		wildcard := false
+		partCount := 0
+
+		for part := range strings.SplitSeq(v, ".") {
+			partCount++
+			if partCount > svPartCount {
+				return p, fmt.Errorf("bad version: %q", s)
+			}
+
+			if part == "x" || part == "X" || part == "*" {
+				wildcard = true
+				continue
+			}
+
+			if wildcard {
+				return p, fmt.Errorf(
+					"bad version: %q: a wildcard may only be followed"+
+						" by wildcards", s)
+			}
+
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 || len(p.parts) == svPartCount ||
+				(len(part) > 1 && part[0] == '0') {
+				return p, fmt.Errorf("bad version: %q", s)
+			}
+
+			p.parts = append(p.parts, n)
+		}
+
+		parts := make([]int, svPartCount)
+		copy(parts, p.parts)
+		p.sv = semver.NewSVOrPanic(parts[0], parts[1], parts[2], nil, nil)
+
+		return p, nil
+	}
+
+	sv := &semver.SV{}
+	if err := setFromVersionText(sv, v); err != nil {
+		return partialSV{}, err
+	}
+
+	return partialSV{
+		parts: []int{sv.Major(), sv.Minor(), sv.Patch()},
+		sv:    sv,
+	}, nil
+}
+
+// parseRangeCmp parses a single comparison
+func parseRangeCmp(s string) (rangeCmp, error) {
+	op := ""
+
+	for _, o := range rangeOps {
+		if strings.HasPrefix(s, o) {
+			op = o
+			break
+		}
+	}
+
+	p, err := parsePartialSV(s[len(op):])
+	if err != nil {
+		return rangeCmp{}, err
+	}
+
+	n := len(p.parts)
+
+	switch op {
+	case "", "=", "!=":
+		if p.isFull() {
+			return rangeCmp{
+				lo: p.sv, loIncl: true,
+				hi: p.sv, hiIncl: true,
+				negate: op == "!=",
+			}, nil
+		}
+
+		return rangeCmp{lo: p.sv, loIncl: true, hi: p.bump(n),
+			negate: op == "!="}, nil
+	case ">":
+		if p.isFull() {
+			return rangeCmp{lo: p.sv}, nil
+		}
+
+		if n == 0 {
+			return rangeCmp{}, fmt.Errorf("bad comparison %q:"+
+				" no version can be greater than every version", s)
+		}
+
+		next := p.bump(n)
+
+		return rangeCmp{
+			lo: semver.NewSVOrPanic(
+				next.Major(), next.Minor(), next.Patch(), nil, nil),
+			loIncl: true,
+		}, nil
+	case ">=":
+		return rangeCmp{lo: p.sv, loIncl: true}, nil
+	case "<":
+		if p.isFull() {
+			return rangeCmp{hi: p.sv}, nil
+		}
+
+		return rangeCmp{hi: p.withLowestPreRel()}, nil
+	case "<=":
+		if p.isFull() {
+			return rangeCmp{hi: p.sv, hiIncl: true}, nil
+		}
+
+		return rangeCmp{hi: p.bump(n)}, nil
+	case "^":
+		bumpPart := n
+		for i, part := range p.parts {
+			if part != 0 {
+				bumpPart = i + 1
+				break
+			}
+		}
+
+		return rangeCmp{lo: p.sv, loIncl: true, hi: p.bump(bumpPart)}, nil
+	}
+
+	// op == "~"
+	return rangeCmp{lo: p.sv, loIncl: true, hi: p.bump(min(n, 2))}, nil
+}
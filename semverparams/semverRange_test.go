@@ -0,0 +1,138 @@
+package semverparams_test
+
+import (
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestRange(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		rangeExpr string
+		in        []string
+		out       []string
+	}{
+		{
+			ID:        testhelper.MkID("exact"),
+			rangeExpr: "v1.2.3",
+			in:        []string{"v1.2.3", "v1.2.3+build"},
+			out:       []string{"v1.2.4", "v1.2.3-rc.1"},
+		},
+		{
+			ID:        testhelper.MkID("not equal"),
+			rangeExpr: "!=1.2.3",
+			in:        []string{"v1.2.4", "v1.2.3-rc.1"},
+			out:       []string{"v1.2.3"},
+		},
+		{
+			ID:        testhelper.MkID("partial"),
+			rangeExpr: "v1.2",
+			in:        []string{"v1.2.0", "v1.2.99"},
+			out:       []string{"v1.2.0-rc.1", "v1.3.0-rc.1", "v1.3.0", "v1.1.9"},
+		},
+		{
+			ID:        testhelper.MkID("partial with x"),
+			rangeExpr: "=v1.x",
+			in:        []string{"v1.0.0", "v1.99.0"},
+			out:       []string{"v2.0.0", "v0.1.0"},
+		},
+		{
+			ID:        testhelper.MkID("partial with several wildcards"),
+			rangeExpr: "1.x.*",
+			in:        []string{"v1.0.0", "v1.99.3"},
+			out:       []string{"v2.0.0", "v0.1.0"},
+		},
+		{
+			ID:        testhelper.MkID("bounded"),
+			rangeExpr: ">=v2.6 <v3",
+			in:        []string{"v2.6.0", "v2.7.1"},
+			out:       []string{"v2.5.9", "v2.6.0-rc.1", "v3.0.0-rc.1", "v3.0.0"},
+		},
+		{
+			ID:        testhelper.MkID("greater than partial"),
+			rangeExpr: ">v1.2",
+			in:        []string{"v1.3.0", "v2.0.0"},
+			out:       []string{"v1.2.9", "v1.3.0-rc.1"},
+		},
+		{
+			ID:        testhelper.MkID("greater than full, less or equal partial"),
+			rangeExpr: ">v1.2.3-rc.1 <=1.2",
+			in:        []string{"v1.2.3-rc.2", "v1.2.3", "v1.2.9"},
+			out:       []string{"v1.2.3-rc.1", "v1.3.0-0", "v1.3.0"},
+		},
+		{
+			ID:        testhelper.MkID("caret"),
+			rangeExpr: "^v1.4.2 || ^0.2.1 || ^0.0.3",
+			in:        []string{"v1.4.2", "v1.9.0", "v0.2.5", "v0.0.3"},
+			out: []string{
+				"v1.4.1", "v2.0.0", "v0.3.0", "v0.2.0", "v0.0.4",
+				"v2.0.0-rc.1",
+			},
+		},
+		{
+			ID:        testhelper.MkID("tilde"),
+			rangeExpr: "~v1.4.2 || ~v3",
+			in:        []string{"v1.4.2", "v1.4.9", "v3.9.0"},
+			out:       []string{"v1.5.0", "v1.4.1", "v4.0.0"},
+		},
+		{
+			ID:        testhelper.MkID("any"),
+			rangeExpr: "*",
+			in:        []string{"v0.0.0", "v99.0.0"},
+		},
+		{
+			ID:        testhelper.MkID("bad - empty"),
+			ExpErr:    testhelper.MkExpErr("the range expression is empty"),
+			rangeExpr: "  ",
+		},
+		{
+			ID:        testhelper.MkID("bad - empty alternative"),
+			ExpErr:    testhelper.MkExpErr("an alternative is empty"),
+			rangeExpr: "v1 ||",
+		},
+		{
+			ID:        testhelper.MkID("bad - version"),
+			ExpErr:    testhelper.MkExpErr(`bad version: "v1.02"`),
+			rangeExpr: ">=v1.02",
+		},
+		{
+			ID: testhelper.MkID("part after a wildcard"),
+			ExpErr: testhelper.MkExpErr(`bad version: "1.x.3"`,
+				"a wildcard may only be followed by wildcards"),
+			rangeExpr: "1.x.3",
+		},
+		{
+			ID:        testhelper.MkID("too many wildcards"),
+			ExpErr:    testhelper.MkExpErr(`bad version: "1.x.x.x"`),
+			rangeExpr: "1.x.x.x",
+		},
+		{
+			ID:        testhelper.MkID("bad - greater than all"),
+			ExpErr:    testhelper.MkExpErr("no version can be greater"),
+			rangeExpr: ">*",
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := semverparams.ParseRange(tc.rangeExpr)
+		if !testhelper.CheckExpErr(t, err, tc) || err != nil {
+			continue
+		}
+
+		for _, v := range tc.in {
+			sv, _ := semver.ParseSV(v)
+			testhelper.DiffBool(t, tc.IDStr(), v+" in range",
+				r.Contains(sv), true)
+		}
+
+		for _, v := range tc.out {
+			sv, _ := semver.ParseSV(v)
+			testhelper.DiffBool(t, tc.IDStr(), v+" in range",
+				r.Contains(sv), false)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package semverparams
 
 import (
+	"strings"
+
 	"github.com/nickwells/param.mod/v7/psetter"
 	"github.com/nickwells/semver.mod/v3/semver"
 )
@@ -12,12 +14,23 @@ type SVSetter struct {
 	psetter.ValueReqMandatory
 
 	Value *semver.SV
+
+	// TagPrefix, if not empty, is the directory part of the tag names of a
+	// sub-module of a monorepo (for instance, "services/billing"). The
+	// parameter value may then be given either as a plain semantic version
+	// number or as the full tag name (such as
+	// "services/billing/v1.4.0"). See TagName.
+	TagPrefix string
 }
 
 // SetWithVal checks that the parameter value meets the checks if any. It
 // returns an error if the check is not satisfied. Only if the check
 // is not violated is the Value set.
 func (svs SVSetter) SetWithVal(_ string, paramVal string) error {
+	if svs.TagPrefix != "" {
+		paramVal = strings.TrimPrefix(paramVal, tagPrefixDir(svs.TagPrefix))
+	}
+
 	v, err := semver.ParseSV(paramVal)
 	if err != nil {
 		return err
@@ -30,11 +43,18 @@ func (svs SVSetter) SetWithVal(_ string, paramVal string) error {
 
 // AllowedValues returns a description of the allowed values
 func (svs SVSetter) AllowedValues() string {
-	return "a semantic version number such as v1.2.3" +
+	av := "a semantic version number such as v1.2.3" +
 		" optionally followed by non-empty lists of dot-separated" +
 		" pre-release and build IDs." +
 		" For instance, 'v1.2.3-a.b.c+x.y.z'." +
 		" See the Semantic Versioning spec for full details."
+
+	if svs.TagPrefix != "" {
+		av += " The version number may be given as a tag name" +
+			" starting with '" + tagPrefixDir(svs.TagPrefix) + "'."
+	}
+
+	return av
 }
 
 // CurrentValue returns the current setting of the parameter value
@@ -48,3 +68,13 @@ func (svs SVSetter) CheckSetter(name string) {
 		panic(name + ": SVSetter Check failed: the Value to be set is nil")
 	}
 }
+
+// setFromVersionText sets the value from the version text using the same
+// rules as the SVSetter except that the leading 'v' is optional. Version
+// text taken from sources other than the command line (manifest files,
+// build information and so on) often omits the 'v'.
+func setFromVersionText(sv *semver.SV, text string) error {
+	svs := SVSetter{Value: sv}
+
+	return svs.SetWithVal("", "v"+strings.TrimPrefix(text, "v"))
+}
@@ -0,0 +1,372 @@
+package semverparams
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/checksetter.mod/v4/checksetter"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// These are the keys used in the text form of the snapshots
+const (
+	snapKeyPrefix         = "prefix"
+	snapKeySemVer         = "semver"
+	snapKeyPreRelIDs      = "pre-rel-IDs"
+	snapKeyBuildIDs       = "build-IDs"
+	snapKeyChannel        = "channel"
+	snapKeySetAt          = "set-at"
+	snapKeyName           = "name"
+	snapKeyPreRelIDChecks = "pre-rel-ID-checks"
+	snapKeyBuildIDChecks  = "build-ID-checks"
+
+	snapKeySep = ": "
+)
+
+// SemverValsSnapshot records the state of a SemverVals after parameter
+// parsing. It can be converted to and from text or JSON so that a run can
+// be recorded and replayed.
+type SemverValsSnapshot struct {
+	Prefix    string   `json:"prefix,omitempty"`
+	SemVer    string   `json:"semver,omitempty"`
+	PreRelIDs []string `json:"preRelIDs,omitempty"`
+	BuildIDs  []string `json:"buildIDs,omitempty"`
+	Channel   string   `json:"channel,omitempty"`
+	// SetAt maps the name of each parameter that has been set to the
+	// places where it was set
+	SetAt map[string][]string `json:"setAt,omitempty"`
+}
+
+// Snapshot returns a snapshot of the current state of the SemverVals
+func (svv SemverVals) Snapshot() SemverValsSnapshot {
+	snap := SemverValsSnapshot{
+		Prefix:    svv.Prefix,
+		PreRelIDs: slices.Clone(svv.PreRelIDs),
+		BuildIDs:  slices.Clone(svv.BuildIDs),
+		Channel:   svv.Channel,
+	}
+
+	if svv.SemVer.HasBeenSet() {
+		snap.SemVer = svv.SemVer.String()
+	}
+
+	for _, pp := range svv.params {
+		for _, p := range []*param.ByName{
+			pp.semver,
+			pp.semverFrom,
+			pp.preRelIDs,
+			pp.buildIDs,
+			pp.channel,
+		} {
+			if !isSet(p) {
+				continue
+			}
+
+			if snap.SetAt == nil {
+				snap.SetAt = map[string][]string{}
+			}
+
+			snap.SetAt[p.Name()] = append(snap.SetAt[p.Name()], p.WhereSet()...)
+		}
+	}
+
+	return snap
+}
+
+// Restore sets the values in the SemverVals from the snapshot. The values
+// are checked in the same way as when they are set by the parameters
+// except that the Channel is only checked to be a valid channel name as
+// the channel table is not known. Note that the record of where the values
+// were set cannot be restored.
+func (snap SemverValsSnapshot) Restore(svv *SemverVals) error {
+	sv := semver.SV{}
+
+	if snap.SemVer != "" {
+		svs := SVSetter{Value: &sv}
+		if err := svs.SetWithVal("", snap.SemVer); err != nil {
+			return err
+		}
+	}
+
+	for _, idl := range []struct {
+		ids    []string
+		idType string
+		chk    func(string) error
+	}{
+		{snap.PreRelIDs, "pre-release", semver.CheckPreRelID},
+		{snap.BuildIDs, "build", semver.CheckBuildID},
+	} {
+		for _, id := range idl.ids {
+			if err := idl.chk(id); err != nil {
+				return fmt.Errorf("bad %s ID: %w", idl.idType, err)
+			}
+		}
+	}
+
+	if snap.Channel != "" {
+		if err := checkChannelName(snap.Channel); err != nil {
+			return err
+		}
+	}
+
+	svv.Prefix = snap.Prefix
+	svv.SemVer = sv
+	svv.PreRelIDs = slices.Clone(snap.PreRelIDs)
+	svv.BuildIDs = slices.Clone(snap.BuildIDs)
+	svv.Channel = snap.Channel
+
+	return nil
+}
+
+// MarshalText returns the snapshot in text form, one "key: value" line per
+// value. There is a "set-at" line for each place where a parameter was set
+// giving the parameter name and the place.
+func (snap SemverValsSnapshot) MarshalText() ([]byte, error) {
+	var b bytes.Buffer
+
+	tw := snapTextWriter{w: &b}
+	tw.write(snapKeyPrefix, snap.Prefix)
+	tw.write(snapKeySemVer, snap.SemVer)
+	tw.write(snapKeyPreRelIDs, strings.Join(snap.PreRelIDs, "."))
+	tw.write(snapKeyBuildIDs, strings.Join(snap.BuildIDs, "."))
+	tw.write(snapKeyChannel, snap.Channel)
+
+	for _, name := range slices.Sorted(maps.Keys(snap.SetAt)) {
+		for _, where := range snap.SetAt[name] {
+			tw.write(snapKeySetAt, name+snapKeySep+where)
+		}
+	}
+
+	if tw.err != nil {
+		return nil, tw.err
+	}
+
+	return b.Bytes(), nil
+}
+
+// UnmarshalText sets the snapshot from its text form (see MarshalText)
+func (snap *SemverValsSnapshot) UnmarshalText(text []byte) error {
+	s := SemverValsSnapshot{}
+
+	err := parseSnapText(text, func(key, val string) error {
+		switch key {
+		case snapKeyPrefix:
+			s.Prefix = val
+		case snapKeySemVer:
+			s.SemVer = val
+		case snapKeyPreRelIDs:
+			s.PreRelIDs = strings.Split(val, ".")
+		case snapKeyBuildIDs:
+			s.BuildIDs = strings.Split(val, ".")
+		case snapKeyChannel:
+			s.Channel = val
+		case snapKeySetAt:
+			name, where, ok := strings.Cut(val, snapKeySep)
+			if !ok {
+				return fmt.Errorf("bad %q value: %q", key, val)
+			}
+
+			if s.SetAt == nil {
+				s.SetAt = map[string][]string{}
+			}
+
+			s.SetAt[name] = append(s.SetAt[name], where)
+		default:
+			return fmt.Errorf("unknown key: %q", key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*snap = s
+
+	return nil
+}
+
+// svvSnapJSON has the same fields as the SemverValsSnapshot but without the
+// methods so that the standard JSON encoding is used rather than the text
+// form
+type svvSnapJSON SemverValsSnapshot
+
+// MarshalJSON returns the snapshot as a JSON object
+func (snap SemverValsSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(svvSnapJSON(snap))
+}
+
+// UnmarshalJSON sets the snapshot from a JSON object
+func (snap *SemverValsSnapshot) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*svvSnapJSON)(snap))
+}
+
+// SemverChecksSnapshot records the state of a SemverChecks after parameter
+// parsing. The checks are recorded as the source text of the check
+// expressions as given to the parameters. It can be converted to and from
+// text or JSON so that a run can be recorded and replayed.
+type SemverChecksSnapshot struct {
+	Name           string `json:"name,omitempty"`
+	PreRelIDChecks string `json:"preRelIDChecks,omitempty"`
+	BuildIDChecks  string `json:"buildIDChecks,omitempty"`
+}
+
+// Snapshot returns a snapshot of the current state of the SemverChecks
+func (svCks SemverChecks) Snapshot() SemverChecksSnapshot {
+	return SemverChecksSnapshot{
+		Name:           svCks.Name,
+		PreRelIDChecks: svCks.preRelIDChecksText,
+		BuildIDChecks:  svCks.buildIDChecksText,
+	}
+}
+
+// Restore sets the checks in the SemverChecks by parsing the check
+// expressions in the snapshot. It returns an error if either expression
+// cannot be parsed, in which case the SemverChecks is unchanged.
+func (snap SemverChecksSnapshot) Restore(svCks *SemverChecks) error {
+	parser, err := checksetter.FindParser[[]string](
+		checksetter.StringSliceCheckerName)
+	if err != nil {
+		return err
+	}
+
+	var preRelIDChecks, buildIDChecks []check.ValCk[[]string]
+
+	if snap.PreRelIDChecks != "" {
+		preRelIDChecks, err = parser.Parse(snap.PreRelIDChecks)
+		if err != nil {
+			return fmt.Errorf("bad pre-release ID checks: %w", err)
+		}
+	}
+
+	if snap.BuildIDChecks != "" {
+		buildIDChecks, err = parser.Parse(snap.BuildIDChecks)
+		if err != nil {
+			return fmt.Errorf("bad build ID checks: %w", err)
+		}
+	}
+
+	svCks.Name = snap.Name
+	svCks.PreRelIDChecks = preRelIDChecks
+	svCks.preRelIDChecksText = snap.PreRelIDChecks
+	svCks.BuildIDChecks = buildIDChecks
+	svCks.buildIDChecksText = snap.BuildIDChecks
+
+	return nil
+}
+
+// MarshalText returns the snapshot in text form, one "key: value" line per
+// value
+func (snap SemverChecksSnapshot) MarshalText() ([]byte, error) {
+	var b bytes.Buffer
+
+	tw := snapTextWriter{w: &b}
+	tw.write(snapKeyName, snap.Name)
+	tw.write(snapKeyPreRelIDChecks, snap.PreRelIDChecks)
+	tw.write(snapKeyBuildIDChecks, snap.BuildIDChecks)
+
+	if tw.err != nil {
+		return nil, tw.err
+	}
+
+	return b.Bytes(), nil
+}
+
+// UnmarshalText sets the snapshot from its text form (see MarshalText)
+func (snap *SemverChecksSnapshot) UnmarshalText(text []byte) error {
+	s := SemverChecksSnapshot{}
+
+	err := parseSnapText(text, func(key, val string) error {
+		switch key {
+		case snapKeyName:
+			s.Name = val
+		case snapKeyPreRelIDChecks:
+			s.PreRelIDChecks = val
+		case snapKeyBuildIDChecks:
+			s.BuildIDChecks = val
+		default:
+			return fmt.Errorf("unknown key: %q", key)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	*snap = s
+
+	return nil
+}
+
+// svCksSnapJSON has the same fields as the SemverChecksSnapshot but without
+// the methods so that the standard JSON encoding is used rather than the
+// text form
+type svCksSnapJSON SemverChecksSnapshot
+
+// MarshalJSON returns the snapshot as a JSON object
+func (snap SemverChecksSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(svCksSnapJSON(snap))
+}
+
+// UnmarshalJSON sets the snapshot from a JSON object
+func (snap *SemverChecksSnapshot) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, (*svCksSnapJSON)(snap))
+}
+
+// snapTextWriter writes the lines of the text form of a snapshot. It
+// records the first error found.
+type snapTextWriter struct {
+	w   *bytes.Buffer
+	err error
+}
+
+// write writes the key and value if the value is not empty. A value
+// containing a newline cannot be written.
+func (tw *snapTextWriter) write(key, val string) {
+	if val == "" || tw.err != nil {
+		return
+	}
+
+	if strings.ContainsAny(val, "\r\n") {
+		tw.err = fmt.Errorf("the %q value contains a newline: %q", key, val)
+		return
+	}
+
+	tw.w.WriteString(key + snapKeySep + val + "\n")
+}
+
+// parseSnapText splits the text form of a snapshot into keys and values
+// and calls the set function for each. Blank lines are ignored.
+func parseSnapText(text []byte, set func(key, val string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(line, snapKeySep)
+		if !ok {
+			return fmt.Errorf("line %d: missing %q: %q",
+				lineNum, snapKeySep, line)
+		}
+
+		if err := set(key, val); err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,224 @@
+package semverparams_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestSnapshot(t *testing.T) {
+	const (
+		preRelIDChecks = `Length(EQ(2))`
+		buildIDChecks  = `Length(LT(3))`
+	)
+
+	svv := semverparams.SemverVals{}
+	svCks := semverparams.SemverChecks{}
+	ps := paramset.NewNoHelpNoExitNoErrRpt(
+		semverparams.AddSemverGroup,
+		svv.AddSemverParam(&svCks),
+		svv.AddIDParams(nil),
+		svv.AddChannelParam(&svCks),
+		svCks.AddCheckParams(),
+	)
+	ps.Parse([]string{
+		"-semver", "v1.2.3-rc.1",
+		"-build-IDs", "b.7",
+		"-channel", "rc",
+		"-pre-rel-ID-checks", preRelIDChecks,
+		"-build-ID-checks", buildIDChecks,
+	})
+
+	if errMap := ps.Errors(); len(errMap) != 0 {
+		t.Fatal("unexpected errors:", errMap)
+	}
+
+	svvSnap := svv.Snapshot()
+	expSvvSnap := semverparams.SemverValsSnapshot{
+		SemVer:   "v1.2.3-rc.1",
+		BuildIDs: []string{"b", "7"},
+		Channel:  "rc",
+		SetAt: map[string][]string{
+			"semver": {
+				`[command line]: Supplied Parameter:2: "-semver" "v1.2.3-rc.1"`,
+			},
+			"build-IDs": {
+				`[command line]: Supplied Parameter:4: "-build-IDs" "b.7"`,
+			},
+			"channel": {
+				`[command line]: Supplied Parameter:6: "-channel" "rc"`,
+			},
+		},
+	}
+
+	if err := testhelper.DiffVals(svvSnap, expSvvSnap); err != nil {
+		t.Error("SemverVals snapshot:", err)
+	}
+
+	svCksSnap := svCks.Snapshot()
+	expSvCksSnap := semverparams.SemverChecksSnapshot{
+		PreRelIDChecks: preRelIDChecks,
+		BuildIDChecks:  buildIDChecks,
+	}
+
+	if err := testhelper.DiffVals(svCksSnap, expSvCksSnap); err != nil {
+		t.Error("SemverChecks snapshot:", err)
+	}
+
+	t.Run("text", func(t *testing.T) {
+		text, err := svvSnap.MarshalText()
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		testhelper.DiffString(t, "SemverVals", "text", string(text),
+			"semver: v1.2.3-rc.1\n"+
+				"build-IDs: b.7\n"+
+				"channel: rc\n"+
+				`set-at: build-IDs: [command line]: Supplied Parameter:4:`+
+				` "-build-IDs" "b.7"`+"\n"+
+				`set-at: channel: [command line]: Supplied Parameter:6:`+
+				` "-channel" "rc"`+"\n"+
+				`set-at: semver: [command line]: Supplied Parameter:2:`+
+				` "-semver" "v1.2.3-rc.1"`+"\n")
+
+		var svvSnap2 semverparams.SemverValsSnapshot
+		if err := svvSnap2.UnmarshalText(text); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := testhelper.DiffVals(svvSnap2, expSvvSnap); err != nil {
+			t.Error("SemverVals text round trip:", err)
+		}
+
+		text, err = svCksSnap.MarshalText()
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		var svCksSnap2 semverparams.SemverChecksSnapshot
+		if err := svCksSnap2.UnmarshalText(text); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := testhelper.DiffVals(svCksSnap2, expSvCksSnap); err != nil {
+			t.Error("SemverChecks text round trip:", err)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(svCksSnap)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		testhelper.DiffString(t, "SemverChecks", "JSON", string(data),
+			`{"preRelIDChecks":"Length(EQ(2))",`+
+				`"buildIDChecks":"Length(LT(3))"}`)
+
+		var svCksSnap2 semverparams.SemverChecksSnapshot
+		if err := json.Unmarshal(data, &svCksSnap2); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := testhelper.DiffVals(svCksSnap2, expSvCksSnap); err != nil {
+			t.Error("SemverChecks JSON round trip:", err)
+		}
+
+		data, err = json.Marshal(svvSnap)
+		if err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		var svvSnap2 semverparams.SemverValsSnapshot
+		if err := json.Unmarshal(data, &svvSnap2); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		if err := testhelper.DiffVals(svvSnap2, expSvvSnap); err != nil {
+			t.Error("SemverVals JSON round trip:", err)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		svvRestored := semverparams.SemverVals{}
+		if err := svvSnap.Restore(&svvRestored); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		testhelper.DiffString(t, "SemverVals", "semver",
+			svvRestored.SemVer.String(), "v1.2.3-rc.1")
+		testhelper.DiffStringSlice(t, "SemverVals", "build IDs",
+			svvRestored.BuildIDs, []string{"b", "7"})
+		testhelper.DiffString(t, "SemverVals", "channel",
+			svvRestored.Channel, "rc")
+
+		svCksRestored := semverparams.SemverChecks{}
+		if err := svCksSnap.Restore(&svCksRestored); err != nil {
+			t.Fatal("unexpected error:", err)
+		}
+
+		testhelper.DiffInt(t, "SemverChecks", "pre-release ID checks",
+			len(svCksRestored.PreRelIDChecks), 1)
+		testhelper.DiffInt(t, "SemverChecks", "build ID checks",
+			len(svCksRestored.BuildIDChecks), 1)
+
+		if err := testhelper.DiffVals(svCksRestored.Snapshot(),
+			expSvCksSnap); err != nil {
+			t.Error("SemverChecks restored snapshot:", err)
+		}
+	})
+}
+
+func TestSnapshotErrs(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		text string
+	}{
+		{
+			ID:     testhelper.MkID("unknown key"),
+			ExpErr: testhelper.MkExpErr(`line 2: unknown key: "colour"`),
+			text:   "semver: v1.2.3\ncolour: blue\n",
+		},
+		{
+			ID:     testhelper.MkID("no separator"),
+			ExpErr: testhelper.MkExpErr(`line 1: missing ": ": "semver"`),
+			text:   "semver\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		var snap semverparams.SemverValsSnapshot
+
+		err := snap.UnmarshalText([]byte(tc.text))
+		testhelper.CheckExpErr(t, err, tc)
+	}
+
+	restoreTestCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		snap semverparams.SemverValsSnapshot
+	}{
+		{
+			ID: testhelper.MkID("restore a bad semver"),
+			ExpErr: testhelper.MkExpErr(
+				"bad semantic version ID - it does not start with a 'v'"),
+			snap: semverparams.SemverValsSnapshot{SemVer: "1.2.3"},
+		},
+		{
+			ID: testhelper.MkID("restore a bad channel"),
+			ExpErr: testhelper.MkExpErr(
+				`bad release channel name "r_c"`),
+			snap: semverparams.SemverValsSnapshot{Channel: "r_c"},
+		},
+	}
+
+	for _, tc := range restoreTestCases {
+		err := tc.snap.Restore(&semverparams.SemverVals{})
+		testhelper.CheckExpErr(t, err, tc)
+	}
+}
@@ -0,0 +1,190 @@
+package semverparams
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// SVFormatData holds the parts of a semantic version number made available
+// to a format template
+type SVFormatData struct {
+	// Version is the full version number, as given by semver.SV.String
+	Version string
+
+	Major int
+	Minor int
+	Patch int
+
+	PreRelIDs []string
+	BuildIDs  []string
+
+	// PreRel and Build are the pre-release and build IDs joined with '.'
+	PreRel string
+	Build  string
+
+	HasPreRel bool
+	HasBuild  bool
+}
+
+// NewSVFormatData returns the format template data for the semantic version
+// number
+func NewSVFormatData(sv *semver.SV) SVFormatData {
+	preRelIDs := sv.PreRelIDs()
+	buildIDs := sv.BuildIDs()
+
+	return SVFormatData{
+		Version:   sv.String(),
+		Major:     sv.Major(),
+		Minor:     sv.Minor(),
+		Patch:     sv.Patch(),
+		PreRelIDs: preRelIDs,
+		BuildIDs:  buildIDs,
+		PreRel:    strings.Join(preRelIDs, "."),
+		Build:     strings.Join(buildIDs, "."),
+		HasPreRel: len(preRelIDs) > 0,
+		HasBuild:  len(buildIDs) > 0,
+	}
+}
+
+// svFormatPresets maps the names of the preset formats to their templates
+var svFormatPresets = map[string]string{
+	"full": "{{.Version}}",
+	"no-v": "{{.Major}}.{{.Minor}}.{{.Patch}}" +
+		"{{if .HasPreRel}}-{{.PreRel}}{{end}}" +
+		"{{if .HasBuild}}+{{.Build}}{{end}}",
+	"core":        "v{{.Major}}.{{.Minor}}.{{.Patch}}",
+	"core-no-v":   "{{.Major}}.{{.Minor}}.{{.Patch}}",
+	"major-minor": "v{{.Major}}.{{.Minor}}",
+	"major":       "v{{.Major}}",
+	"underscore":  "{{.Major}}_{{.Minor}}_{{.Patch}}",
+}
+
+// svFormatDefault is the name of the preset format used if no other has
+// been given
+const svFormatDefault = "full"
+
+// svFormatFuncs are the functions available to a format template
+var svFormatFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// SVFormat records a format for semantic version numbers
+type SVFormat struct {
+	// Text is the format as given; either the name of a preset or a
+	// template
+	Text string
+
+	tmpl *template.Template
+}
+
+// ParseSVFormat converts the format text into an SVFormat. The text may be
+// the name of one of the SVFormatPresets or else a text/template which is
+// given an SVFormatData. The template is checked by formatting a sample
+// version number so that any errors are found now rather than when it is
+// used.
+func ParseSVFormat(text string) (SVFormat, error) {
+	tmplText := text
+	if preset, ok := svFormatPresets[text]; ok {
+		tmplText = preset
+	}
+
+	tmpl, err := template.New("semver-format").
+		Funcs(svFormatFuncs).
+		Option("missingkey=error").
+		Parse(tmplText)
+	if err != nil {
+		return SVFormat{}, fmt.Errorf("bad format: %w", err)
+	}
+
+	f := SVFormat{Text: text, tmpl: tmpl}
+
+	sample := semver.NewSVOrPanic(1, 2, 3,
+		[]string{"rc", "1"}, []string{"build", "7"})
+	if _, err := f.Format(sample); err != nil {
+		return SVFormat{}, err
+	}
+
+	return f, nil
+}
+
+// Format returns the semantic version number formatted according to the
+// SVFormat. If the SVFormat has not been set the "full" preset is used.
+func (f SVFormat) Format(sv *semver.SV) (string, error) {
+	tmpl := f.tmpl
+	if tmpl == nil {
+		df, err := ParseSVFormat(svFormatDefault)
+		if err != nil {
+			return "", err
+		}
+
+		tmpl = df.tmpl
+	}
+
+	var b strings.Builder
+
+	if err := tmpl.Execute(&b, NewSVFormatData(sv)); err != nil {
+		return "", fmt.Errorf("bad format: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// FormatSV formats the semantic version number according to the format
+// text (see ParseSVFormat)
+func FormatSV(format string, sv *semver.SV) (string, error) {
+	f, err := ParseSVFormat(format)
+	if err != nil {
+		return "", err
+	}
+
+	return f.Format(sv)
+}
+
+// SVFormatPresets returns a copy of the map from the names of the preset
+// formats to their templates
+func SVFormatPresets() map[string]string {
+	return maps.Clone(svFormatPresets)
+}
+
+// svFormatPresetNames returns the names of the preset formats, sorted
+func svFormatPresetNames() []string {
+	return slices.Sorted(maps.Keys(svFormatPresets))
+}
+
+// AddFormatParam returns a function that will add a parameter for setting
+// the Format to the passed PSet. The format is checked when the parameter
+// is set so any error is reported as a parameter error.
+func (svv *SemverVals) AddFormatParam() param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		prefix := ""
+		if svv.Prefix != "" {
+			prefix = svv.Prefix + "-"
+		}
+
+		groupName, err := svv.addGroup(ps)
+		if err != nil {
+			return err
+		}
+
+		ps.Add(prefix+"semver-format",
+			SVFormatSetter{Value: &svv.Format},
+			"specify the format in which the "+semver.Name+
+				" should be shown",
+			param.AltNames(prefix+"svn-format"),
+			param.GroupName(groupName),
+		)
+
+		return nil
+	}
+}
+
+// FormattedSemVer returns the SemVer formatted according to the Format
+func (svv SemverVals) FormattedSemVer() (string, error) {
+	return svv.Format.Format(&svv.SemVer)
+}
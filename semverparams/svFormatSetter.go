@@ -0,0 +1,54 @@
+package semverparams
+
+import (
+	"strings"
+
+	"github.com/nickwells/param.mod/v7/psetter"
+)
+
+// SVFormatSetter is a parameter setter which will set the format to be used
+// for semantic version numbers. It satisfies the param.Setter interface and
+// so can be used when specifying a command line argument using the param
+// package.
+type SVFormatSetter struct {
+	psetter.ValueReqMandatory
+
+	Value *SVFormat
+}
+
+// SetWithVal parses the parameter value as a format. It returns an error if
+// the format is not a preset name or a valid template. Only if the format
+// is valid is the Value set.
+func (sfs SVFormatSetter) SetWithVal(_ string, paramVal string) error {
+	f, err := ParseSVFormat(paramVal)
+	if err != nil {
+		return err
+	}
+
+	*sfs.Value = f
+
+	return nil
+}
+
+// AllowedValues returns a description of the allowed values
+func (sfs SVFormatSetter) AllowedValues() string {
+	return "either the name of a preset format (one of: " +
+		strings.Join(svFormatPresetNames(), ", ") + ")" +
+		" or a Go text/template which is given the parts of the version:" +
+		" Version, Major, Minor, Patch, PreRelIDs, BuildIDs," +
+		" PreRel, Build, HasPreRel and HasBuild." +
+		" For instance, '{{.Major}}_{{.Minor}}_{{.Patch}}'." +
+		" The 'join' function is also available."
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (sfs SVFormatSetter) CurrentValue() string {
+	return sfs.Value.Text
+}
+
+// CheckSetter panics if the setter has not been properly created
+func (sfs SVFormatSetter) CheckSetter(name string) {
+	if sfs.Value == nil {
+		panic(name + ": SVFormatSetter Check failed: the Value to be set is nil")
+	}
+}
@@ -0,0 +1,149 @@
+package semverparams_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestFormatSV(t *testing.T) {
+	sv := semver.NewSVOrPanic(1, 2, 3,
+		[]string{"rc", "1"}, []string{"build", "7"})
+
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		format string
+		expStr string
+	}{
+		{
+			ID:     testhelper.MkID("preset: full"),
+			format: "full",
+			expStr: "v1.2.3-rc.1+build.7",
+		},
+		{
+			ID:     testhelper.MkID("preset: no-v"),
+			format: "no-v",
+			expStr: "1.2.3-rc.1+build.7",
+		},
+		{
+			ID:     testhelper.MkID("preset: major-minor"),
+			format: "major-minor",
+			expStr: "v1.2",
+		},
+		{
+			ID:     testhelper.MkID("preset: underscore"),
+			format: "underscore",
+			expStr: "1_2_3",
+		},
+		{
+			ID:     testhelper.MkID("template"),
+			format: `{{.Major}}.{{.Minor}}{{if .HasPreRel}} ({{join .PreRelIDs "-"}}){{end}}`,
+			expStr: "1.2 (rc-1)",
+		},
+		{
+			ID:     testhelper.MkID("bad template syntax"),
+			ExpErr: testhelper.MkExpErr("bad format: ", "unclosed action"),
+			format: "{{.Major",
+		},
+		{
+			ID: testhelper.MkID("bad template field"),
+			ExpErr: testhelper.MkExpErr("bad format: ",
+				"can't evaluate field Nonesuch"),
+			format: "{{.Nonesuch}}",
+		},
+	}
+
+	for _, tc := range testCases {
+		s, err := semverparams.FormatSV(tc.format, sv)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "formatted semver",
+				s, tc.expStr)
+		}
+	}
+}
+
+func TestSVFormatPresets(t *testing.T) {
+	presets := semverparams.SVFormatPresets()
+	testhelper.DiffString(t, "SVFormatPresets", "core",
+		presets["core"], "v{{.Major}}.{{.Minor}}.{{.Patch}}")
+
+	presets["core"] = "changed"
+
+	s, err := semverparams.FormatSV("core",
+		semver.NewSVOrPanic(1, 2, 3, nil, nil))
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	testhelper.DiffString(t, "changed copy", "formatted semver", s, "v1.2.3")
+}
+
+func TestFormatParam(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		args    []string
+		expStr  string
+		expErrs errutil.ErrMap
+	}{
+		{
+			ID:     testhelper.MkID("default format"),
+			args:   []string{"-semver", "v1.2.3-rc.1"},
+			expStr: "v1.2.3-rc.1",
+		},
+		{
+			ID:     testhelper.MkID("preset"),
+			args:   []string{"-semver", "v1.2.3-rc.1", "-semver-format", "core"},
+			expStr: "v1.2.3",
+		},
+		{
+			ID:     testhelper.MkID("bad format"),
+			args:   []string{"-semver", "v1.2.3", "-semver-format", "{{.X}}"},
+			expStr: "v1.2.3",
+			expErrs: errutil.ErrMap{
+				"semver-format": []error{
+					errors.New("bad format: template: semver-format:1:2:" +
+						" executing \"semver-format\" at <.X>:" +
+						" can't evaluate field X in type" +
+						" semverparams.SVFormatData" +
+						"\nAt: [command line]: Supplied Parameter:4:" +
+						" \"-semver-format\" \"{{.X}}\""),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		svv := semverparams.SemverVals{}
+		ps := paramset.NewNoHelpNoExitNoErrRpt(
+			semverparams.AddSemverGroup,
+			svv.AddSemverParam(nil),
+			svv.AddFormatParam(),
+		)
+		ps.Parse(tc.args)
+
+		if tc.expErrs == nil {
+			tc.expErrs = errutil.ErrMap{}
+		}
+
+		if err := tc.expErrs.Matches(ps.Errors()); err != nil {
+			t.Log(tc.IDStr())
+			t.Error(err)
+		}
+
+		s, err := svv.FormattedSemVer()
+		if err != nil {
+			t.Log(tc.IDStr())
+			t.Error("\t: unexpected error:", err)
+
+			continue
+		}
+
+		testhelper.DiffString(t, tc.IDStr(), "formatted semver", s, tc.expStr)
+	}
+}
@@ -0,0 +1,8 @@
+/*
+Package semverparamstest offers helpers for writing table-driven tests of
+programs which use the semverparams package. It provides builders for
+paramtest.Parser test cases over SemverVals and SemverChecks, a comparison
+function which ignores the unexported fields of those types and helpers
+for constructing the errors reported by the final checks.
+*/
+package semverparamstest
@@ -0,0 +1,86 @@
+package semverparamstest
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nickwells/checksetter.mod/v4/checksetter"
+	"github.com/nickwells/errutil.mod/errutil"
+)
+
+// FinalChecksCategory is the category of the errors reported by the final
+// checks
+const FinalChecksCategory = "Final Checks"
+
+// IDPart identifies the part of a semantic version number to which the
+// checks are applied
+type IDPart int
+
+// These are the parts of a semantic version number which can be checked
+const (
+	PreRelIDs IDPart = iota
+	BuildIDs
+)
+
+// String returns the name of the part as shown in the final check errors
+func (part IDPart) String() string {
+	switch part {
+	case PreRelIDs:
+		return "PreRelIDs"
+	case BuildIDs:
+		return "BuildIDs"
+	}
+
+	return fmt.Sprintf("IDPart(%d)", int(part))
+}
+
+// FinalCheckErr returns the error reported by the final checks when the IDs
+// of the given part fail a check with the given error. The desc should be
+// the Desc of the SemverVals being checked.
+func FinalCheckErr(desc string, part IDPart, checkErr error) error {
+	errPfx := ""
+	if desc != "" {
+		errPfx = desc + ": "
+	}
+
+	return fmt.Errorf("%sBad %s: %w", errPfx, part, checkErr)
+}
+
+// CheckErr applies the checks given by the expression to the IDs and
+// returns the error from the first check which fails, or nil if they all
+// pass. The expression is given as it would be for the pre-rel-ID-checks
+// and build-ID-checks parameters. It will panic if the expression cannot
+// be parsed as this is a coding error in the test.
+func CheckErr(checkExpr string, ids []string) error {
+	checks, err := checksetter.FindParserOrPanic[[]string](
+		checksetter.StringSliceCheckerName).Parse(checkExpr)
+	if err != nil {
+		panic(fmt.Errorf("bad check expression %q: %w", checkExpr, err))
+	}
+
+	for _, chk := range checks {
+		if err := chk(ids); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ExpFinalCheckErrs returns the errors expected from parsing when the IDs of
+// the given part fail the checks given by the expression (see
+// CheckErr). The desc should be the Desc of the SemverVals being
+// checked. It will panic if the IDs pass the checks.
+func ExpFinalCheckErrs(
+	desc string, part IDPart, checkExpr string, ids []string,
+) errutil.ErrMap {
+	checkErr := CheckErr(checkExpr, ids)
+	if checkErr == nil {
+		panic(errors.New("the IDs pass the checks: " + checkExpr))
+	}
+
+	errs := errutil.ErrMap{}
+	errs.AddError(FinalChecksCategory, FinalCheckErr(desc, part, checkErr))
+
+	return errs
+}
@@ -0,0 +1,102 @@
+package semverparamstest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nickwells/param.mod/v7/paramtest"
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/semverparams.mod/v6/semverparamstest"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const rcChecks = `Or(Length(EQ(0)),` +
+	`And(Length(EQ(2)),` +
+	` SliceByPos(EQ("rc"), MatchesPattern("^[1-9][0-9]*$", "numeric"))))`
+
+func TestFinalCheckErrs(t *testing.T) {
+	testCases := []paramtest.Parser{
+		semverparamstest.NewParser(t,
+			testhelper.MkID("good semver"),
+			nil,
+			semverparamstest.Pair{
+				Vals:   &semverparams.SemverVals{},
+				Checks: &semverparams.SemverChecks{},
+			},
+			semverparamstest.Pair{
+				Vals: &semverparams.SemverVals{
+					SemVer: *semver.NewSVOrPanic(1, 2, 3,
+						[]string{"rc", "1"}, nil),
+				},
+			},
+			"-semver", "v1.2.3-rc.1", "-pre-rel-ID-checks", rcChecks),
+		semverparamstest.NewParser(t,
+			testhelper.MkID("bad semver pre-release IDs"),
+			semverparamstest.ExpFinalCheckErrs("", semverparamstest.PreRelIDs,
+				rcChecks, []string{"rc", "1", "x"}),
+			semverparamstest.Pair{
+				Vals:   &semverparams.SemverVals{},
+				Checks: &semverparams.SemverChecks{},
+			},
+			semverparamstest.Pair{
+				Vals: &semverparams.SemverVals{
+					SemVer: *semver.NewSVOrPanic(1, 2, 3,
+						[]string{"rc", "1", "x"}, nil),
+				},
+			},
+			"-semver", "v1.2.3-rc.1.x", "-pre-rel-ID-checks", rcChecks),
+		semverparamstest.NewParser(t,
+			testhelper.MkID("bad build IDs, with desc"),
+			semverparamstest.ExpFinalCheckErrs("desc", semverparamstest.BuildIDs,
+				rcChecks, []string{"rc", "01"}),
+			semverparamstest.Pair{
+				Vals:        &semverparams.SemverVals{Desc: "desc"},
+				Checks:      &semverparams.SemverChecks{Name: "x"},
+				AddIDParams: true,
+			},
+			semverparamstest.Pair{
+				Vals: &semverparams.SemverVals{
+					Desc:     "desc",
+					BuildIDs: []string{"rc", "01"},
+				},
+				AddIDParams: true,
+			},
+			"-build-IDs", "rc.01", "-x-build-ID-checks", rcChecks),
+	}
+
+	for _, tc := range testCases {
+		_ = tc.Test(t)
+	}
+}
+
+func TestFinalCheckErr(t *testing.T) {
+	checkErr := errors.New("oops")
+
+	testhelper.DiffString(t, "no desc", "error",
+		semverparamstest.FinalCheckErr("",
+			semverparamstest.PreRelIDs, checkErr).Error(),
+		"Bad PreRelIDs: oops")
+	testhelper.DiffString(t, "desc", "error",
+		semverparamstest.FinalCheckErr("d",
+			semverparamstest.BuildIDs, checkErr).Error(),
+		"d: Bad BuildIDs: oops")
+
+	if err := semverparamstest.CheckErr(rcChecks,
+		[]string{"rc", "2"}); err != nil {
+		t.Error("unexpected check failure:", err)
+	}
+}
+
+func TestCmpPairs(t *testing.T) {
+	if err := semverparamstest.CmpPairs(1, semverparamstest.Pair{}); err == nil {
+		t.Error("expected an error comparing with a non-Pair value")
+	}
+
+	if err := semverparamstest.CmpPairs(
+		semverparamstest.Pair{Vals: &semverparams.SemverVals{Prefix: "a"}},
+		semverparamstest.Pair{Vals: &semverparams.SemverVals{Prefix: "b"}},
+	); err == nil {
+		t.Error("expected an error comparing different Pairs")
+	}
+}
@@ -0,0 +1,249 @@
+package semverparamstest
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+)
+
+// GenConfig controls the distribution of the values made by a Generator.
+// The probabilities should be between 0 and 1.
+type GenConfig struct {
+	// MaxPart is the largest value of the major, minor and patch parts
+	MaxPart int
+	// V0Prob is the probability that a version has a major part of 0
+	// (that it is an initial development version rather than a stable one)
+	V0Prob float64
+
+	// PreRelProb and BuildProb are the probabilities that a version has
+	// pre-release IDs and build IDs respectively
+	PreRelProb float64
+	BuildProb  float64
+
+	// MaxIDs is the largest number of IDs in a list of pre-release or
+	// build IDs, a list always has at least one ID
+	MaxIDs int
+	// NumericIDProb is the probability that an ID is all numeric
+	NumericIDProb float64
+	// MaxIDLen is the longest length of an ID which is not all numeric
+	MaxIDLen int
+}
+
+// DefaultGenConfig is a GenConfig giving a reasonable spread of values
+var DefaultGenConfig = GenConfig{
+	MaxPart:       20,
+	V0Prob:        0.25,
+	PreRelProb:    0.4,
+	BuildProb:     0.3,
+	MaxIDs:        4,
+	NumericIDProb: 0.4,
+	MaxIDLen:      8,
+}
+
+// idChars are the characters which may appear in an ID
+const idChars = "0123456789" +
+	"abcdefghijklmnopqrstuvwxyz" +
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
+	"-"
+
+// Generator makes random semantic version numbers and lists of IDs for
+// use in property-based and fuzz tests. The values are given by the seed
+// so a Generator made with the same seed and GenConfig will always make
+// the same sequence of values. A Generator is not safe for concurrent use.
+type Generator struct {
+	cfg GenConfig
+	rnd *rand.Rand
+}
+
+// NewGenerator returns a Generator seeded with the given value and making
+// values with the distribution given by the GenConfig. Any zero maximum in
+// the GenConfig is replaced by the value from DefaultGenConfig.
+func NewGenerator(seed uint64, cfg GenConfig) *Generator {
+	if cfg.MaxPart <= 0 {
+		cfg.MaxPart = DefaultGenConfig.MaxPart
+	}
+
+	if cfg.MaxIDs <= 0 {
+		cfg.MaxIDs = DefaultGenConfig.MaxIDs
+	}
+
+	if cfg.MaxIDLen <= 0 {
+		cfg.MaxIDLen = DefaultGenConfig.MaxIDLen
+	}
+
+	return &Generator{
+		cfg: cfg,
+		rnd: rand.New(rand.NewPCG(seed, seed^0x5eed)), //nolint:gosec
+	}
+}
+
+// chance returns true with the given probability
+func (g *Generator) chance(prob float64) bool {
+	return g.rnd.Float64() < prob
+}
+
+// part returns a value for a major, minor or patch part
+func (g *Generator) part() int {
+	return g.rnd.IntN(g.cfg.MaxPart + 1)
+}
+
+// numericID returns an all numeric ID. If leadingZeroOK is false it will
+// not have a leading zero (unless it is just "0").
+func (g *Generator) numericID(leadingZeroOK bool) string {
+	id := strconv.Itoa(g.rnd.IntN(1000))
+	if leadingZeroOK && g.chance(0.2) { //nolint:mnd
+		id = "0" + id
+	}
+
+	return id
+}
+
+// alnumID returns an ID which is not all numeric
+func (g *Generator) alnumID() string {
+	n := 1 + g.rnd.IntN(g.cfg.MaxIDLen)
+
+	var b strings.Builder
+
+	for range n {
+		b.WriteByte(idChars[g.rnd.IntN(len(idChars))])
+	}
+
+	id := b.String()
+	if strings.Trim(id, "0123456789") == "" {
+		// make sure it is not all numeric
+		id += "x"
+	}
+
+	return id
+}
+
+// idList returns a non-empty list of IDs
+func (g *Generator) idList(leadingZeroOK bool) []string {
+	ids := make([]string, 1+g.rnd.IntN(g.cfg.MaxIDs))
+
+	for i := range ids {
+		if g.chance(g.cfg.NumericIDProb) {
+			ids[i] = g.numericID(leadingZeroOK)
+		} else {
+			ids[i] = g.alnumID()
+		}
+	}
+
+	return ids
+}
+
+// PreRelIDs returns a valid, non-empty, list of pre-release IDs
+func (g *Generator) PreRelIDs() []string {
+	return g.idList(false)
+}
+
+// BuildIDs returns a valid, non-empty, list of build IDs. Unlike
+// pre-release IDs, numeric build IDs may have leading zeros.
+func (g *Generator) BuildIDs() []string {
+	return g.idList(true)
+}
+
+// SV returns a valid semantic version number
+func (g *Generator) SV() *semver.SV {
+	major := 0
+	if !g.chance(g.cfg.V0Prob) {
+		major = 1 + g.rnd.IntN(g.cfg.MaxPart)
+	}
+
+	var preRelIDs, buildIDs []string
+
+	if g.chance(g.cfg.PreRelProb) {
+		preRelIDs = g.PreRelIDs()
+	}
+
+	if g.chance(g.cfg.BuildProb) {
+		buildIDs = g.BuildIDs()
+	}
+
+	return semver.NewSVOrPanic(major, g.part(), g.part(), preRelIDs, buildIDs)
+}
+
+// badIDChars are characters which may not appear in an ID
+const badIDChars = "_!~ /,=*"
+
+// badIDChar returns a character which may not appear in an ID
+func (g *Generator) badIDChar() string {
+	i := g.rnd.IntN(len(badIDChars))
+	return badIDChars[i : i+1]
+}
+
+// spoilIDs returns the IDs, joined with '.', with one of them made
+// invalid: either emptied or given a bad character. If leadingZero is true
+// an all numeric ID may also be given a leading zero (which is only invalid
+// for pre-release IDs).
+func (g *Generator) spoilIDs(ids []string, leadingZero bool) string {
+	ids = append([]string(nil), ids...)
+	i := g.rnd.IntN(len(ids))
+
+	const spoilings = 3
+
+	switch n := g.rnd.IntN(spoilings); {
+	case n == 0:
+		ids[i] = ""
+	case n == 1 && leadingZero:
+		ids[i] = "0" + strconv.Itoa(1+g.rnd.IntN(1000))
+	default:
+		pos := g.rnd.IntN(len(ids[i]) + 1)
+		ids[i] = ids[i][:pos] + g.badIDChar() + ids[i][pos:]
+	}
+
+	return strings.Join(ids, ".")
+}
+
+// InvalidPreRelIDs returns a near-miss invalid list of pre-release IDs,
+// joined with '.', differing from a valid list in just one ID
+func (g *Generator) InvalidPreRelIDs() string {
+	return g.spoilIDs(g.PreRelIDs(), true)
+}
+
+// InvalidBuildIDs returns a near-miss invalid list of build IDs, joined
+// with '.', differing from a valid list in just one ID
+func (g *Generator) InvalidBuildIDs() string {
+	return g.spoilIDs(g.BuildIDs(), false)
+}
+
+// InvalidSVString returns a near-miss invalid semantic version number: a
+// valid one with a single change making it invalid
+func (g *Generator) InvalidSVString() string {
+	sv := g.SV()
+	core := "v" + strconv.Itoa(sv.Major()) +
+		"." + strconv.Itoa(sv.Minor()) +
+		"." + strconv.Itoa(sv.Patch())
+
+	suffix := ""
+	if sv.HasPreRelIDs() {
+		suffix += "-" + strings.Join(sv.PreRelIDs(), ".")
+	}
+
+	if sv.HasBuildIDs() {
+		suffix += "+" + strings.Join(sv.BuildIDs(), ".")
+	}
+
+	const spoilings = 8
+
+	switch g.rnd.IntN(spoilings) {
+	case 0: // no leading 'v'
+		return core[1:] + suffix
+	case 1: // a missing part
+		return core[:strings.LastIndex(core, ".")] + suffix
+	case 2: // an extra part
+		return core + "." + strconv.Itoa(g.part()) + suffix
+	case 3: // a leading zero
+		return strings.Replace(core, ".", ".0", 1) + suffix
+	case 4: // a non-numeric part
+		return strings.Replace(core, ".", ".x", 1) + suffix
+	case 5: // an empty list of pre-release IDs
+		return core + "-"
+	case 6: // a bad pre-release ID
+		return core + "-" + g.InvalidPreRelIDs()
+	default: // a bad build ID
+		return core + "+" + g.InvalidBuildIDs()
+	}
+}
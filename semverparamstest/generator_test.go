@@ -0,0 +1,140 @@
+package semverparamstest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nickwells/semver.mod/v3/semver"
+	"github.com/nickwells/semverparams.mod/v6/semverparamstest"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+const genCount = 500
+
+func TestGeneratorDeterminism(t *testing.T) {
+	g1 := semverparamstest.NewGenerator(42, semverparamstest.DefaultGenConfig)
+	g2 := semverparamstest.NewGenerator(42, semverparamstest.DefaultGenConfig)
+	g3 := semverparamstest.NewGenerator(43, semverparamstest.DefaultGenConfig)
+
+	var sameAsOtherSeed int
+
+	for i := range genCount {
+		sv1, sv2, sv3 := g1.SV().String(), g2.SV().String(), g3.SV().String()
+		if testhelper.DiffString(t, "same seed", "SV", sv1, sv2) {
+			t.Log("\t: value:", i)
+		}
+
+		if sv1 == sv3 {
+			sameAsOtherSeed++
+		}
+
+		if testhelper.DiffString(t, "same seed", "invalid SV",
+			g1.InvalidSVString(), g2.InvalidSVString()) {
+			t.Log("\t: value:", i)
+		}
+	}
+
+	if sameAsOtherSeed == genCount {
+		t.Error("different seeds gave the same values")
+	}
+}
+
+func TestGeneratorValid(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		cfg semverparamstest.GenConfig
+	}{
+		{
+			ID:  testhelper.MkID("default config"),
+			cfg: semverparamstest.DefaultGenConfig,
+		},
+		{
+			ID: testhelper.MkID("all v0, all IDs, all numeric"),
+			cfg: semverparamstest.GenConfig{
+				V0Prob:        1,
+				PreRelProb:    1,
+				BuildProb:     1,
+				NumericIDProb: 1,
+			},
+		},
+		{
+			ID: testhelper.MkID("all stable, all IDs, none numeric"),
+			cfg: semverparamstest.GenConfig{
+				PreRelProb: 1,
+				BuildProb:  1,
+				MaxIDs:     1,
+				MaxIDLen:   1,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		g := semverparamstest.NewGenerator(1, tc.cfg)
+
+		for range genCount {
+			sv := g.SV()
+			if _, err := semver.ParseSV(sv.String()); err != nil {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: generated an invalid SV: %q: %v", sv, err)
+			}
+
+			if tc.cfg.V0Prob == 1 && sv.Major() != 0 {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: expected a v0 SV, got: %q", sv)
+			}
+
+			if tc.cfg.V0Prob == 0 && sv.Major() == 0 {
+				t.Log(tc.IDStr())
+				t.Errorf("\t: expected a stable SV, got: %q", sv)
+			}
+
+			if err := semver.CheckAllPreRelIDs(g.PreRelIDs()); err != nil {
+				t.Log(tc.IDStr())
+				t.Error("\t: generated bad pre-release IDs:", err)
+			}
+
+			for _, id := range g.BuildIDs() {
+				if err := semver.CheckBuildID(id); err != nil {
+					t.Log(tc.IDStr())
+					t.Error("\t: generated a bad build ID:", err)
+				}
+			}
+		}
+	}
+}
+
+func TestGeneratorInvalid(t *testing.T) {
+	g := semverparamstest.NewGenerator(7, semverparamstest.DefaultGenConfig)
+
+	for range genCount {
+		if s := g.InvalidSVString(); semverOK(s) {
+			t.Errorf("generated a valid SV as invalid: %q", s)
+		}
+
+		if s := g.InvalidPreRelIDs(); semver.CheckAllPreRelIDs(
+			strings.Split(s, ".")) == nil {
+			t.Errorf("generated valid pre-release IDs as invalid: %q", s)
+		}
+
+		if s := g.InvalidBuildIDs(); buildIDsOK(s) {
+			t.Errorf("generated valid build IDs as invalid: %q", s)
+		}
+	}
+}
+
+// semverOK returns true if the string is a valid semantic version number
+func semverOK(s string) bool {
+	_, err := semver.ParseSV(s)
+	return err == nil
+}
+
+// buildIDsOK returns true if all the dot-separated build IDs are valid
+func buildIDsOK(s string) bool {
+	for _, id := range strings.Split(s, ".") {
+		if semver.CheckBuildID(id) != nil {
+			return false
+		}
+	}
+
+	return true
+}
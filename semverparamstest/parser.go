@@ -0,0 +1,139 @@
+package semverparamstest
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nickwells/errutil.mod/errutil"
+	"github.com/nickwells/param.mod/v7/param"
+	"github.com/nickwells/param.mod/v7/paramset"
+	"github.com/nickwells/param.mod/v7/paramtest"
+	"github.com/nickwells/semverparams.mod/v6/semverparams"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+// Pair holds the SemverVals and SemverChecks whose parameters are to be
+// tested. Either may be nil in which case its parameters are not added.
+type Pair struct {
+	Vals   *semverparams.SemverVals
+	Checks *semverparams.SemverChecks
+
+	// AddIDParams, if true, causes the parameters for setting the
+	// pre-release and build IDs to be added as well as the parameters for
+	// setting the semantic version number
+	AddIDParams bool
+}
+
+// MakePSet returns a PSet with the parameters for the Pair added. The PSet
+// is made without the standard help parameters and it will not report
+// errors or exit so it is suitable for testing. The test is failed if the
+// parameters cannot be added.
+func MakePSet(t testing.TB, p *Pair) *param.PSet {
+	t.Helper()
+
+	psof := []param.PSetOptFunc{}
+
+	if p.Vals != nil {
+		psof = append(psof, p.Vals.AddSemverParam(p.Checks))
+
+		if p.AddIDParams {
+			psof = append(psof, p.Vals.AddIDParams(p.Checks))
+		}
+	}
+
+	if p.Checks != nil {
+		psof = append(psof, p.Checks.AddCheckParams())
+	}
+
+	ps := paramset.NewNoHelpNoExitNoErrRpt()
+
+	for _, f := range psof {
+		if err := f(ps); err != nil {
+			t.Fatal("cannot add the parameters: ", err)
+		}
+	}
+
+	return ps
+}
+
+// ignoredFields gives the paths of the fields ignored when comparing Pairs.
+// The Checks and the BuildIDSource of the Vals are ignored as they hold
+// functions which cannot be compared.
+var ignoredFields = [][]string{
+	{"Checks"},
+	{"Vals", "BuildIDSource"},
+}
+
+// exportedVals returns a copy of the SemverVals with only the exported
+// fields set. The unexported fields record the parameters and where the
+// values were set and so will differ between the value and the expected
+// value. A nil SemverVals is returned unchanged.
+func exportedVals(svv *semverparams.SemverVals) *semverparams.SemverVals {
+	if svv == nil {
+		return nil
+	}
+
+	cp := &semverparams.SemverVals{}
+	from := reflect.ValueOf(svv).Elem()
+	to := reflect.ValueOf(cp).Elem()
+
+	for i := range from.NumField() {
+		if from.Type().Field(i).IsExported() {
+			to.Field(i).Set(from.Field(i))
+		}
+	}
+
+	return cp
+}
+
+// CmpPairs compares the value with the expected value and returns an error
+// if they differ. Both values must be Pairs. Only the exported fields of
+// the Vals are compared. It is suitable for use as the CheckFunc of a
+// paramtest.Parser.
+func CmpPairs(iVal, iExpVal any) error {
+	val, ok := iVal.(Pair)
+	if !ok {
+		return errors.New("Bad value: not a semverparamstest.Pair")
+	}
+
+	expVal, ok := iExpVal.(Pair)
+	if !ok {
+		return errors.New("Bad expected value: not a semverparamstest.Pair")
+	}
+
+	val.Vals = exportedVals(val.Vals)
+	expVal.Vals = exportedVals(expVal.Vals)
+
+	return testhelper.DiffVals(val, expVal, ignoredFields...)
+}
+
+// NewParser returns a paramtest.Parser ready to be added to the test
+// cases. The PSet is made from the initial values (see MakePSet) and the
+// values after parsing the args are compared with the expected values (see
+// CmpPairs). The errs give the errors expected from parsing, they may be
+// nil if no errors are expected. The test is failed if the PSet cannot be
+// made.
+func NewParser(
+	t testing.TB,
+	id testhelper.ID,
+	errs errutil.ErrMap,
+	initVals, expVals Pair,
+	args ...string,
+) paramtest.Parser {
+	t.Helper()
+
+	if errs == nil {
+		errs = errutil.ErrMap{}
+	}
+
+	return paramtest.Parser{
+		ID:             id,
+		ExpParseErrors: errs,
+		Val:            initVals,
+		Ps:             MakePSet(t, &initVals),
+		ExpVal:         expVals,
+		Args:           args,
+		CheckFunc:      CmpPairs,
+	}
+}